@@ -2,19 +2,14 @@ package es
 
 import (
 	"context"
-	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/denkhaus/es/core"
-	"github.com/olivere/elastic/v7"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
-var (
-	clientInstance *core.ElasticClientImpl
-)
-
 // func ensureIndices(ctx context.Context, client *core.ElasticClient) error {
 // 	if err := client.EnsureIndexWithMapping(
 // 		ctx, defaults.ElasticSearchTransfersIndexName,
@@ -33,54 +28,91 @@ var (
 // 	return nil
 // }
 
-type ElasticClient interface {
-	ClearScroll(ctx context.Context, scrollID string) error
-	Count(ctx context.Context, index string, query string) (int64, error)
-	CreateIndex(ctx context.Context, index string) error
-	DoCreate(ctx context.Context, indexName string, data map[string]interface{}) error
-	DoIndex(ctx context.Context, indexName string, data map[string]interface{}) error
-	DoIndexWithNameProvider(ctx context.Context, data map[string]core.IndexNameProvider) error
-	EnsureIndexWithMapping(ctx context.Context, indexName string, mapping string) error
-	EnumerateItems(ctx context.Context, indexName string, query elastic.Query, sorter elastic.Sorter, onItem func(item json.RawMessage, nCurrentItem int64, nTotalItems int64, commit bool) error) error
-	FlushIndex(ctx context.Context, index string) error
-	GetIndices(prefixes []string) (map[string][]string, error)
-	MarshalWithNameAndIDProvider(ctx context.Context, data core.IndexNameAndIDProvider) error
-	Ping() *elastic.PingService
-	PutMapping(ctx context.Context, index string, root string, key string, valueType string) error
-	RunBulkProcessor(ctx context.Context, p *core.BulkProcessorParameters) (*elastic.BulkProcessor, error)
-	ScrollService(index string, query elastic.Query, sorter elastic.Sorter) *elastic.ScrollService
-	Search(ctx context.Context, p *core.SearchParameters) (*elastic.SearchResult, error)
-	SearchWithDSL(ctx context.Context, index string, query string) (*elastic.SearchResult, error)
-	UnmarshalMostRecent(ctx context.Context, indexName string, query elastic.Query, timestampField string, target interface{}) error
-	UnmarshalOne(ctx context.Context, indexName string, query elastic.Query, target interface{}) error
-}
+// ElasticClient is the public, backend-neutral client surface. See
+// core.ElasticClient for the method set; it's aliased here so existing
+// imports of es.ElasticClient keep working.
+type ElasticClient = core.ElasticClient
 
-func Get(ctx context.Context, endpoint, userName, password string, healthCheckInterval time.Duration, sniff bool) (ElasticClient, error) {
-	if clientInstance == nil {
-		client, err := core.NewClient(
-			endpoint,
-			userName,
-			password,
-			healthCheckInterval,
-			sniff,
-		)
+// Config is aliased here so callers don't need to import core directly to
+// call New.
+type Config = core.Config
 
-		if err != nil {
-			return nil, errors.Wrap(err, "NewClient")
-		}
+// Observability is aliased here so callers don't need to import core
+// directly to set Config.Observability.
+type Observability = core.Observability
 
-		info, code, err := client.Ping().Do(ctx)
+// New dials endpoint and returns a fresh ElasticClient every call - unlike
+// Get, it never shares state across callers, so each gets an isolated
+// client against its own cluster.
+func New(ctx context.Context, cfg Config) (ElasticClient, error) {
+	driver := cfg.Driver
+	if driver == core.DriverAuto {
+		detected, err := core.DetectDriver(ctx, cfg)
 		if err != nil {
-			return nil, errors.Wrap(err, "Ping")
+			return nil, errors.Wrap(err, "DetectDriver")
 		}
+		driver = detected
+	}
+
+	var (
+		client core.ElasticClient
+		err    error
+	)
+
+	switch driver {
+	case core.DriverV8:
+		client, err = core.NewClientV8FromConfig(cfg)
+	default:
+		client, err = core.NewClientFromConfig(cfg)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig")
+	}
+
+	info, err := client.Ping(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Ping")
+	}
 
-		zlog.Debug("elasticsearch client created",
-			zap.Int("code", code),
-			zap.String("version", info.Version.Number),
-		)
+	logger := cfg.Logger
+	if logger == nil {
+		logger = zlog
+	}
+
+	logger.Debug("elasticsearch client created",
+		zap.String("version", info.Version),
+		zap.String("driver", driver.String()),
+	)
 
-		clientInstance = client
+	if cfg.Observability != nil {
+		client = core.NewObservableClient(client, cfg.Observability)
 	}
 
-	return clientInstance, nil
+	return client, nil
+}
+
+var (
+	clientInstance core.ElasticClient
+	clientOnce     sync.Once
+	clientErr      error
+)
+
+// Get returns a process-wide Elasticsearch client, creating it on first
+// call and reusing it on every call after.
+//
+// Deprecated: Get's single shared client can't talk to more than one
+// cluster per process and can't be isolated between tests. Use New instead.
+func Get(ctx context.Context, endpoint, userName, password string, healthCheckInterval time.Duration, sniff bool) (ElasticClient, error) {
+	clientOnce.Do(func() {
+		clientInstance, clientErr = New(ctx, Config{
+			Endpoint:            endpoint,
+			Username:            userName,
+			Password:            password,
+			HealthcheckInterval: healthCheckInterval,
+			Sniff:               sniff,
+		})
+	})
+
+	return clientInstance, clientErr
 }
@@ -0,0 +1,376 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Aggregation abstracts a search aggregation independent of the underlying
+// driver (see ElasticClient's doc comment on the olivere-compatibility of
+// these interfaces).
+type Aggregation interface {
+	Source() (interface{}, error)
+}
+
+// RawAggregation wraps a pre-built JSON aggregation body so it satisfies
+// Aggregation without depending on any driver-specific aggregation builder.
+// Useful for the v8 driver, which speaks raw JSON rather than olivere's
+// aggregation DSL types.
+type RawAggregation json.RawMessage
+
+// Source implements Aggregation.
+func (a RawAggregation) Source() (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(a, &v); err != nil {
+		return nil, errors.Wrap(err, "Unmarshal")
+	}
+	return v, nil
+}
+
+// Bucket is a single bucket of a terms or date_histogram aggregation.
+// Sub-aggregations nested inside the bucket are kept as raw JSON in
+// Aggregations, keyed by name; each can be unmarshaled into the matching
+// result type (TermsResult, StatsResult, ...) with decodeAggregation or by
+// walking Bucket.Aggregations directly. The exported Decode* functions take
+// a *SearchResult and do not accept a Bucket's Aggregations.
+type Bucket struct {
+	Key          interface{}
+	KeyAsString  string
+	DocCount     int64
+	Aggregations map[string]json.RawMessage
+}
+
+// UnmarshalJSON splits the well-known bucket fields (key, key_as_string,
+// doc_count) from whatever sub-aggregations the bucket carries, so the
+// latter remain available for recursive decoding.
+func (b *Bucket) UnmarshalJSON(data []byte) error {
+	rest, err := splitKnownFields(data, map[string]interface{}{
+		"key":           &b.Key,
+		"key_as_string": &b.KeyAsString,
+		"doc_count":     &b.DocCount,
+	})
+	if err != nil {
+		return err
+	}
+
+	b.Aggregations = rest
+	return nil
+}
+
+// TermsResult is the decoded response of a terms aggregation.
+type TermsResult struct {
+	DocCountErrorUpperBound int64    `json:"doc_count_error_upper_bound"`
+	SumOtherDocCount        int64    `json:"sum_other_doc_count"`
+	Buckets                 []Bucket `json:"buckets"`
+}
+
+// DateHistogramResult is the decoded response of a date_histogram aggregation.
+type DateHistogramResult struct {
+	Buckets []Bucket `json:"buckets"`
+}
+
+// StatsResult is the decoded response of a stats aggregation.
+type StatsResult struct {
+	Count int64    `json:"count"`
+	Min   *float64 `json:"min"`
+	Max   *float64 `json:"max"`
+	Avg   *float64 `json:"avg"`
+	Sum   *float64 `json:"sum"`
+}
+
+// CardinalityResult is the decoded response of a cardinality aggregation.
+type CardinalityResult struct {
+	Value int64 `json:"value"`
+}
+
+// NestedResult is the decoded response of a nested aggregation. Its
+// sub-aggregations are kept as raw JSON in Aggregations.
+type NestedResult struct {
+	DocCount     int64
+	Aggregations map[string]json.RawMessage
+}
+
+// UnmarshalJSON splits doc_count from the nested aggregation's sub-aggregations.
+func (n *NestedResult) UnmarshalJSON(data []byte) error {
+	rest, err := splitKnownFields(data, map[string]interface{}{
+		"doc_count": &n.DocCount,
+	})
+	if err != nil {
+		return err
+	}
+
+	n.Aggregations = rest
+	return nil
+}
+
+// FiltersBucket is a single named bucket of a filters aggregation.
+type FiltersBucket struct {
+	DocCount     int64
+	Aggregations map[string]json.RawMessage
+}
+
+// UnmarshalJSON splits doc_count from the filters bucket's sub-aggregations.
+func (b *FiltersBucket) UnmarshalJSON(data []byte) error {
+	rest, err := splitKnownFields(data, map[string]interface{}{
+		"doc_count": &b.DocCount,
+	})
+	if err != nil {
+		return err
+	}
+
+	b.Aggregations = rest
+	return nil
+}
+
+// FiltersResult is the decoded response of a filters aggregation, keyed by
+// the filter name given in the request.
+type FiltersResult struct {
+	Buckets map[string]FiltersBucket `json:"buckets"`
+}
+
+// CompositeBucket is a single bucket of a composite aggregation.
+type CompositeBucket struct {
+	Key          map[string]interface{}
+	DocCount     int64
+	Aggregations map[string]json.RawMessage
+}
+
+// UnmarshalJSON splits key and doc_count from the composite bucket's
+// sub-aggregations.
+func (b *CompositeBucket) UnmarshalJSON(data []byte) error {
+	rest, err := splitKnownFields(data, map[string]interface{}{
+		"key":       &b.Key,
+		"doc_count": &b.DocCount,
+	})
+	if err != nil {
+		return err
+	}
+
+	b.Aggregations = rest
+	return nil
+}
+
+// CompositeResult is the decoded response of a composite aggregation.
+// AfterKey, when non-nil, is fed back into the next page's composite
+// aggregation (see EnumerateComposite).
+type CompositeResult struct {
+	AfterKey map[string]interface{} `json:"after_key"`
+	Buckets  []CompositeBucket      `json:"buckets"`
+}
+
+// splitKnownFields unmarshals data as a JSON object, decodes the fields
+// named in known into their target pointers, and returns everything else as
+// raw JSON, keyed by field name. It's how the Decode* bucket types separate
+// their own fields from arbitrary nested sub-aggregations.
+func splitKnownFields(data []byte, known map[string]interface{}) (map[string]json.RawMessage, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "Unmarshal")
+	}
+
+	for key, target := range known {
+		v, ok := raw[key]
+		if !ok {
+			continue
+		}
+
+		if err := json.Unmarshal(v, target); err != nil {
+			return nil, errors.Wrapf(err, "Unmarshal %s", key)
+		}
+
+		delete(raw, key)
+	}
+
+	return raw, nil
+}
+
+// aggregationsOf parses a SearchResult's raw Aggregations into a map keyed
+// by aggregation name, ready for decodeAggregation to pull individual
+// aggregations out of.
+func aggregationsOf(result *SearchResult) (map[string]json.RawMessage, error) {
+	if len(result.Aggregations) == 0 {
+		return nil, errors.Errorf("search result carries no aggregations")
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(result.Aggregations, &m); err != nil {
+		return nil, errors.Wrap(err, "Unmarshal")
+	}
+
+	return m, nil
+}
+
+// decodeAggregation looks up name in aggs and unmarshals it into out. It
+// backs both the top-level Decode* functions (against a SearchResult) and
+// sub-aggregation decoding against a Bucket's, NestedResult's, or
+// FiltersBucket's own Aggregations map.
+func decodeAggregation(aggs map[string]json.RawMessage, name string, out interface{}) error {
+	raw, ok := aggs[name]
+	if !ok {
+		return errors.Errorf("aggregation %q not found", name)
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return errors.Wrapf(err, "Unmarshal %q", name)
+	}
+
+	return nil
+}
+
+// DecodeTerms decodes the named terms aggregation out of result.
+func DecodeTerms(result *SearchResult, name string) (*TermsResult, error) {
+	aggs, err := aggregationsOf(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var out TermsResult
+	if err := decodeAggregation(aggs, name, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DecodeDateHistogram decodes the named date_histogram aggregation out of result.
+func DecodeDateHistogram(result *SearchResult, name string) (*DateHistogramResult, error) {
+	aggs, err := aggregationsOf(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var out DateHistogramResult
+	if err := decodeAggregation(aggs, name, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DecodeStats decodes the named stats aggregation out of result.
+func DecodeStats(result *SearchResult, name string) (*StatsResult, error) {
+	aggs, err := aggregationsOf(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var out StatsResult
+	if err := decodeAggregation(aggs, name, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DecodeCardinality decodes the named cardinality aggregation out of result.
+func DecodeCardinality(result *SearchResult, name string) (*CardinalityResult, error) {
+	aggs, err := aggregationsOf(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var out CardinalityResult
+	if err := decodeAggregation(aggs, name, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DecodeNested decodes the named nested aggregation out of result.
+func DecodeNested(result *SearchResult, name string) (*NestedResult, error) {
+	aggs, err := aggregationsOf(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var out NestedResult
+	if err := decodeAggregation(aggs, name, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DecodeFilters decodes the named filters aggregation out of result.
+func DecodeFilters(result *SearchResult, name string) (*FiltersResult, error) {
+	aggs, err := aggregationsOf(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var out FiltersResult
+	if err := decodeAggregation(aggs, name, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DecodeComposite decodes the named composite aggregation out of result.
+func DecodeComposite(result *SearchResult, name string) (*CompositeResult, error) {
+	aggs, err := aggregationsOf(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var out CompositeResult
+	if err := decodeAggregation(aggs, name, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// EnumerateComposite streams a composite aggregation page by page, advancing
+// after_key on every round, mirroring how EnumerateItems streams search
+// hits. aggSource builds the composite aggregation for a round given the
+// previous round's after key (nil for the first round); callers typically
+// call elastic.NewCompositeAggregation().Sources(...).AggregateAfter(after)
+// for the v7 driver, or wrap a raw composite body in RawAggregation for v8.
+// Enumeration stops once a page comes back with no buckets.
+func EnumerateComposite(
+	ctx context.Context,
+	client ElasticClient,
+	index string,
+	query Query,
+	aggName string,
+	aggSource func(after map[string]interface{}) Aggregation,
+	onPage func(buckets []CompositeBucket) error,
+) error {
+
+	var after map[string]interface{}
+
+	for {
+		res, err := client.Search(ctx, &SearchParameters{
+			Index:         index,
+			Query:         query,
+			AggregateOnly: true,
+			Aggregations:  map[string]Aggregation{aggName: aggSource(after)},
+		})
+		if err != nil {
+			return errors.Wrap(err, "Search")
+		}
+
+		page, err := DecodeComposite(res, aggName)
+		if err != nil {
+			return errors.Wrap(err, "DecodeComposite")
+		}
+
+		if len(page.Buckets) == 0 {
+			return nil
+		}
+
+		if err := onPage(page.Buckets); err != nil {
+			return err
+		}
+
+		if page.AfterKey == nil {
+			return nil
+		}
+
+		after = page.AfterKey
+	}
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeTerms(t *testing.T) {
+	result := &SearchResult{Aggregations: json.RawMessage(`{
+		"by_status": {
+			"doc_count_error_upper_bound": 0,
+			"sum_other_doc_count": 3,
+			"buckets": [
+				{"key": "open", "doc_count": 5},
+				{"key": "closed", "doc_count": 2, "avg_age": {"avg": 12.5}}
+			]
+		}
+	}`)}
+
+	out, err := DecodeTerms(result, "by_status")
+	if err != nil {
+		t.Fatalf("DecodeTerms: %v", err)
+	}
+
+	if out.SumOtherDocCount != 3 {
+		t.Errorf("SumOtherDocCount = %d, want 3", out.SumOtherDocCount)
+	}
+	if len(out.Buckets) != 2 {
+		t.Fatalf("len(Buckets) = %d, want 2", len(out.Buckets))
+	}
+	if out.Buckets[0].Key != "open" || out.Buckets[0].DocCount != 5 {
+		t.Errorf("Buckets[0] = %+v, want key=open doc_count=5", out.Buckets[0])
+	}
+	var sub StatsResult
+	if err := decodeAggregation(out.Buckets[1].Aggregations, "avg_age", &sub); err != nil {
+		t.Fatalf("decodeAggregation(avg_age sub-aggregation): %v", err)
+	}
+	if sub.Avg == nil || *sub.Avg != 12.5 {
+		t.Errorf("sub.Avg = %v, want 12.5", sub.Avg)
+	}
+}
+
+func TestDecodeTermsMissingAggregation(t *testing.T) {
+	result := &SearchResult{Aggregations: json.RawMessage(`{"other": {}}`)}
+
+	if _, err := DecodeTerms(result, "by_status"); err == nil {
+		t.Fatal("want error for missing aggregation, got nil")
+	}
+}
+
+func TestAggregationsOfNoAggregations(t *testing.T) {
+	result := &SearchResult{}
+
+	if _, err := aggregationsOf(result); err == nil {
+		t.Fatal("want error when SearchResult carries no aggregations, got nil")
+	}
+}
+
+func TestDecodeComposite(t *testing.T) {
+	result := &SearchResult{Aggregations: json.RawMessage(`{
+		"page": {
+			"after_key": {"id": "42"},
+			"buckets": [
+				{"key": {"id": "1"}, "doc_count": 1}
+			]
+		}
+	}`)}
+
+	out, err := DecodeComposite(result, "page")
+	if err != nil {
+		t.Fatalf("DecodeComposite: %v", err)
+	}
+
+	if out.AfterKey["id"] != "42" {
+		t.Errorf("AfterKey = %+v, want id=42", out.AfterKey)
+	}
+	if len(out.Buckets) != 1 || out.Buckets[0].DocCount != 1 {
+		t.Errorf("Buckets = %+v, want one bucket with doc_count=1", out.Buckets)
+	}
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// bulkIngestThreshold is the batch size above which DoIndex, DoCreate, and
+// DoIndexWithNameProvider switch from issuing one request per document to
+// draining the batch through a BulkIngester.
+const bulkIngestThreshold = 10
+
+// ingestViaBulk feeds data through a short-lived BulkIngester, one BulkItem
+// per entry, index fixed across the batch.
+func ingestViaBulk(ctx context.Context, client ElasticClient, indexName string, data map[string]interface{}, action BulkActionType) error {
+	ingester := NewBulkIngester(ctx, client, &BulkIngesterParameters{BatchSize: len(data)})
+
+	for id, doc := range data {
+		if err := ingester.Add(ctx, BulkItem{Action: action, Index: indexName, ID: id, Doc: doc}); err != nil {
+			_ = ingester.Close(ctx)
+			return err
+		}
+	}
+
+	if err := ingester.Close(ctx); err != nil {
+		return err
+	}
+
+	return failedItemsErr(ingester.Stats())
+}
+
+// ingestViaBulkWithNameProvider is the IndexNameProvider sibling of
+// ingestViaBulk, where each document carries its own destination index.
+func ingestViaBulkWithNameProvider(ctx context.Context, client ElasticClient, data map[string]IndexNameProvider) error {
+	ingester := NewBulkIngester(ctx, client, &BulkIngesterParameters{BatchSize: len(data)})
+
+	for id, doc := range data {
+		if err := ingester.Add(ctx, BulkItem{Action: BulkIndex, Index: doc.IndexName(), ID: id, Doc: doc}); err != nil {
+			_ = ingester.Close(ctx)
+			return err
+		}
+	}
+
+	if err := ingester.Close(ctx); err != nil {
+		return err
+	}
+
+	return failedItemsErr(ingester.Stats())
+}
+
+// failedItemsErr reports stats.Failed as an error so ingestViaBulk's callers
+// see a partially-failed bulk commit the same way the direct per-document
+// loop in DoIndex/DoCreate/DoIndexWithNameProvider surfaces a Do/Index
+// error, instead of a commit-level nil that hides per-item failures.
+func failedItemsErr(stats BulkIngesterStats) error {
+	if stats.Failed == 0 {
+		return nil
+	}
+
+	return errors.Errorf("bulk ingest: %d of %d items failed", stats.Failed, stats.Failed+stats.Indexed)
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// BulkActionType is the action a BulkItem performs, as understood by the ES
+// _bulk endpoint.
+type BulkActionType int
+
+const (
+	BulkIndex BulkActionType = iota
+	BulkCreate
+	BulkUpdate
+	BulkDelete
+)
+
+// BulkItem is a single document action to submit via ElasticClient.Bulk or
+// BulkIngester.Add.
+type BulkItem struct {
+	Action BulkActionType
+	Index  string
+	ID     string
+	Doc    interface{} // ignored for BulkDelete
+}
+
+// BulkItemResult is the per-item outcome of a Bulk call, in the same order
+// the BulkItems were submitted.
+type BulkItemResult struct {
+	Index  string
+	ID     string
+	Status int
+	Error  error
+}
+
+// BulkResult is the outcome of a single Bulk call.
+type BulkResult struct {
+	Items       []BulkItemResult
+	HasFailures bool
+	BytesInBody int
+}
+
+// buildBulkBody renders items into the newline-delimited JSON the _bulk
+// endpoint expects. This wire format is identical across ES 6/7/8, so both
+// drivers share it instead of duplicating the encoding.
+func buildBulkBody(items []BulkItem) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+
+	for _, item := range items {
+		meta := map[string]interface{}{"_index": item.Index}
+		if item.ID != "" {
+			meta["_id"] = item.ID
+		}
+
+		var action string
+		switch item.Action {
+		case BulkIndex:
+			action = "index"
+		case BulkCreate:
+			action = "create"
+		case BulkUpdate:
+			action = "update"
+		case BulkDelete:
+			action = "delete"
+		default:
+			return nil, errors.Errorf("unknown bulk action %d", item.Action)
+		}
+
+		if err := enc.Encode(map[string]interface{}{action: meta}); err != nil {
+			return nil, errors.Wrap(err, "Encode")
+		}
+
+		switch item.Action {
+		case BulkIndex, BulkCreate:
+			if err := enc.Encode(item.Doc); err != nil {
+				return nil, errors.Wrap(err, "Encode")
+			}
+		case BulkUpdate:
+			if err := enc.Encode(map[string]interface{}{"doc": item.Doc}); err != nil {
+				return nil, errors.Wrap(err, "Encode")
+			}
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+type bulkResponseWire struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Index  string `json:"_index"`
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// parseBulkResponse decodes a raw _bulk response body into a BulkResult.
+func parseBulkResponse(body []byte) (*BulkResult, error) {
+	var wire bulkResponseWire
+	if err := json.Unmarshal(body, &wire); err != nil {
+		return nil, errors.Wrap(err, "Unmarshal")
+	}
+
+	result := &BulkResult{
+		Items:       make([]BulkItemResult, len(wire.Items)),
+		HasFailures: wire.Errors,
+		BytesInBody: len(body),
+	}
+
+	for i, item := range wire.Items {
+		for _, action := range item {
+			itemResult := BulkItemResult{
+				Index:  action.Index,
+				ID:     action.ID,
+				Status: action.Status,
+			}
+			if action.Error != nil {
+				itemResult.Error = errors.Errorf("%s: %s", action.Error.Type, action.Error.Reason)
+			}
+			result.Items[i] = itemResult
+		}
+	}
+
+	return result, nil
+}
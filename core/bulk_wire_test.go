@@ -0,0 +1,65 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildBulkBody(t *testing.T) {
+	body, err := buildBulkBody([]BulkItem{
+		{Action: BulkIndex, Index: "idx", ID: "1", Doc: map[string]string{"a": "b"}},
+		{Action: BulkDelete, Index: "idx", ID: "2"},
+	})
+	if err != nil {
+		t.Fatalf("buildBulkBody: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(body), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want 3 NDJSON lines (index meta, index doc, delete meta), got %d: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], `"index"`) || !strings.Contains(lines[0], `"_id":"1"`) {
+		t.Errorf("line 0 = %q, want index meta with _id 1", lines[0])
+	}
+	if !strings.Contains(lines[1], `"a":"b"`) {
+		t.Errorf("line 1 = %q, want the document body", lines[1])
+	}
+	if !strings.Contains(lines[2], `"delete"`) || !strings.Contains(lines[2], `"_id":"2"`) {
+		t.Errorf("line 2 = %q, want delete meta with _id 2", lines[2])
+	}
+}
+
+func TestBuildBulkBodyUnknownAction(t *testing.T) {
+	_, err := buildBulkBody([]BulkItem{{Action: BulkActionType(99), Index: "idx"}})
+	if err == nil {
+		t.Fatal("want error for unknown bulk action, got nil")
+	}
+}
+
+func TestParseBulkResponse(t *testing.T) {
+	body := []byte(`{
+		"errors": true,
+		"items": [
+			{"index": {"_index": "idx", "_id": "1", "status": 201}},
+			{"index": {"_index": "idx", "_id": "2", "status": 409, "error": {"type": "version_conflict", "reason": "conflict"}}}
+		]
+	}`)
+
+	result, err := parseBulkResponse(body)
+	if err != nil {
+		t.Fatalf("parseBulkResponse: %v", err)
+	}
+
+	if !result.HasFailures {
+		t.Error("HasFailures = false, want true")
+	}
+	if len(result.Items) != 2 {
+		t.Fatalf("len(Items) = %d, want 2", len(result.Items))
+	}
+	if result.Items[0].Error != nil {
+		t.Errorf("Items[0].Error = %v, want nil", result.Items[0].Error)
+	}
+	if result.Items[1].Error == nil {
+		t.Error("Items[1].Error = nil, want version_conflict error")
+	}
+}
@@ -1,12 +1,9 @@
 package core
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"io"
-	"net/http"
-	"regexp"
 	"strings"
 	"time"
 
@@ -36,67 +33,116 @@ type IndexNameAndIDProvider interface {
 	IndexNameProvider
 }
 
-//get index list from ES and parse indices from it
-//return a map where every prefix from input array is a key
-//and a value is vector of corresponding indices
+// GetIndices returns, for every prefix, the names of indices matched by
+// GetIndicesInfo with that prefix (a "<prefix>*" wildcard; see
+// GetIndicesOptions.Prefixes).
+//
+// Deprecated: use GetIndicesInfo, which also covers date-suffixed and
+// ILM-rollover index names, aliases, and per-index stats.
 func (c *ElasticClientImpl) GetIndices(prefixes []string) (map[string][]string, error) {
 	result := make(map[string][]string)
 
-	req, err := http.NewRequest("GET", c.endpoint+"/_cat/indices?v&s=index", nil)
+	infos, err := c.GetIndicesInfo(context.Background(), GetIndicesOptions{Prefixes: prefixes})
 	if err != nil {
-		return nil, errors.Wrap(err, "NewRequest")
+		return result, err
 	}
 
-	req.SetBasicAuth(c.userName, c.password)
+	for _, prefix := range prefixes {
+		for _, info := range infos {
+			if strings.HasPrefix(info.Name, prefix) {
+				result[prefix] = append(result[prefix], info.Name)
+			}
+		}
+	}
 
-	client := http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return result, nil
+	return result, nil
+}
+
+// GetIndicesInfo lists indices (and, with IncludeAliases, their aliases) via
+// the Cat Indices and Cat Aliases APIs.
+func (c *ElasticClientImpl) GetIndicesInfo(ctx context.Context, opts GetIndicesOptions) ([]IndexInfo, error) {
+	svc := c.client.CatIndices().Bytes("b")
+
+	if patterns := buildIndexPatterns(opts.Prefixes, opts.IncludeHidden); len(patterns) != 0 {
+		svc = svc.Index(strings.Join(patterns, ","))
 	}
 
-	defer resp.Body.Close()
-	scanner := bufio.NewScanner(resp.Body)
-	scanner.Split(bufio.ScanLines)
+	if len(opts.SortBy) != 0 {
+		svc = svc.Sort(opts.SortBy...)
+	}
 
-	var lines []string
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+	rows, err := svc.Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Do")
 	}
 
-	for _, prefix := range prefixes {
-		r, err := regexp.Compile("\\s" + prefix + "-(\\d)*\\s")
-		if err != nil {
-			return result, nil
+	infos := make([]IndexInfo, len(rows))
+	for i, row := range rows {
+		infos[i] = IndexInfo{
+			Name:           row.Index,
+			DocCount:       int64(row.DocsCount),
+			StoreSizeBytes: parseByteCount(row.StoreSize),
+			Health:         row.Health,
+			CreationDate:   row.CreationDate,
 		}
-		for _, line := range lines {
-			match := r.FindString(line)
-			if len(match) != 0 {
-				result[prefix] = append(result[prefix], strings.TrimSpace(match))
-			}
+	}
+
+	if !opts.IncludeAliases {
+		return infos, nil
+	}
+
+	aliasRows, err := c.client.CatAliases().Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Do [aliases]")
+	}
+
+	aliases := make([]catAliasRow, len(aliasRows))
+	for i, row := range aliasRows {
+		aliases[i] = catAliasRow{
+			Index:        row.Index,
+			Alias:        row.Alias,
+			IsWriteIndex: row.IsWriteIndex == "true",
 		}
 	}
 
-	return result, nil
+	return expandWithAliases(infos, aliases), nil
 }
 
 func (c *ElasticClientImpl) Search(ctx context.Context, p *SearchParameters) (
-	*elastic.SearchResult, error) {
+	*SearchResult, error) {
+
+	sorters := make([]elastic.Sorter, len(p.Sorter))
+	for i, s := range p.Sorter {
+		sorters[i] = s
+	}
 
 	searchService := c.client.Search(p.Index).
 		Query(p.Query).
 		From(p.From).
-		SortBy(p.Sorter...)
+		SortBy(sorters...)
 
 	if p.PageSize != 0 {
 		searchService.Size(p.PageSize)
 	}
 
+	if p.AggregateOnly {
+		searchService.Size(0)
+	}
+
+	for name, agg := range p.Aggregations {
+		searchService.Aggregation(name, agg)
+	}
+
 	if len(p.SearchAfter) != 0 {
 		searchService.SearchAfter(p.SearchAfter...)
 	}
 
-	return searchService.Do(ctx)
+	res, err := searchService.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSearchResultFromV7(res), nil
 }
 
 func (c *ElasticClientImpl) MarshalWithNameAndIDProvider(ctx context.Context, data IndexNameAndIDProvider) error {
@@ -112,7 +158,7 @@ func (c *ElasticClientImpl) UnmarshalOne(
 
 	ctx context.Context,
 	indexName string,
-	query elastic.Query,
+	query Query,
 	target interface{},
 
 ) error {
@@ -142,7 +188,7 @@ func (c *ElasticClientImpl) UnmarshalMostRecent(
 
 	ctx context.Context,
 	indexName string,
-	query elastic.Query,
+	query Query,
 	timestampField string,
 	target interface{},
 
@@ -173,14 +219,22 @@ func (c *ElasticClientImpl) UnmarshalMostRecent(
 }
 
 func (c *ElasticClientImpl) SearchWithDSL(ctx context.Context, index, query string) (
-	*elastic.SearchResult, error) {
-	return c.client.Search(index).Source(query).Do(ctx)
+	*SearchResult, error) {
+	res, err := c.client.Search(index).Source(query).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSearchResultFromV7(res), nil
 }
 
+// ScrollService returns the underlying olivere scroll service. It is a v7
+// driver internal used by EnumerateItems, not part of the ElasticClient
+// interface, since *elastic.ScrollService isn't backend-neutral.
 func (c *ElasticClientImpl) ScrollService(
 	index string,
-	query elastic.Query,
-	sorter elastic.Sorter,
+	query Query,
+	sorter Sorter,
 ) *elastic.ScrollService {
 
 	svc := elastic.NewScrollService(c.client)
@@ -192,6 +246,100 @@ func (c *ElasticClientImpl) ScrollService(
 	return svc
 }
 
+// Bulk sends items to the _bulk endpoint in a single request. It underlies
+// BulkIngester, which needs per-item results that *elastic.BulkProcessor
+// (driven by RunBulkProcessor) doesn't expose.
+func (c *ElasticClientImpl) Bulk(ctx context.Context, items []BulkItem) (*BulkResult, error) {
+	body, err := buildBulkBody(items)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method:      "POST",
+		Path:        "/_bulk",
+		Body:        string(body),
+		ContentType: "application/x-ndjson",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "PerformRequest")
+	}
+
+	return parseBulkResponse(res.Body)
+}
+
+// OpenPIT opens a point in time against index, valid until keepAlive
+// elapses or ClosePIT is called. It underlies EnumerateItemsPIT and
+// EnumerateItemsParallel.
+func (c *ElasticClientImpl) OpenPIT(ctx context.Context, index string, keepAlive time.Duration) (string, error) {
+	res, err := elastic.NewOpenPointInTimeService(c.client).
+		Index(index).
+		KeepAlive(keepAlive.String()).
+		Do(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "Do")
+	}
+
+	return res.Id, nil
+}
+
+// ClosePIT releases a point in time opened with OpenPIT.
+func (c *ElasticClientImpl) ClosePIT(ctx context.Context, id string) error {
+	if _, err := elastic.NewClosePointInTimeService(c.client).ID(id).Do(ctx); err != nil {
+		return errors.Wrap(err, "Do")
+	}
+
+	return nil
+}
+
+// SearchAfterPage runs a single page of a point-in-time + search_after
+// search. It underlies EnumerateItemsPIT and EnumerateItemsParallel, which
+// drive it in a loop, feeding SearchAfterResult.LastSort back in as the
+// next page's SearchAfter.
+func (c *ElasticClientImpl) SearchAfterPage(ctx context.Context, p *SearchAfterParameters) (*SearchAfterResult, error) {
+	sorters := make([]elastic.Sorter, len(p.Sorter))
+	for i, s := range p.Sorter {
+		sorters[i] = s
+	}
+
+	src := elastic.NewSearchSource().
+		Query(p.Query).
+		SortBy(sorters...).
+		PointInTime(elastic.NewPointInTime(p.PitID)).
+		Size(p.PageSize)
+
+	if len(p.SearchAfter) != 0 {
+		src = src.SearchAfter(p.SearchAfter...)
+	}
+
+	if p.Slice != nil {
+		src = src.Slice(elastic.NewSliceQuery().Id(p.Slice.ID).Max(p.Slice.Max))
+	}
+
+	res, err := c.client.Search().SearchSource(src).Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Do")
+	}
+
+	return newSearchAfterResultFromV7(res), nil
+}
+
+func newSearchAfterResultFromV7(res *elastic.SearchResult) *SearchAfterResult {
+	hits := make([]json.RawMessage, len(res.Hits.Hits))
+
+	var lastSort []interface{}
+	for i, hit := range res.Hits.Hits {
+		hits[i] = hit.Source
+		lastSort = hit.Sort
+	}
+
+	return &SearchAfterResult{
+		Hits:      hits,
+		TotalHits: res.Hits.TotalHits.Value,
+		LastSort:  lastSort,
+	}
+}
+
 func (c *ElasticClientImpl) ClearScroll(ctx context.Context, scrollID string) error {
 	if scrollID == "" {
 		return nil
@@ -207,8 +355,8 @@ func (c *ElasticClientImpl) ClearScroll(ctx context.Context, scrollID string) er
 func (c *ElasticClientImpl) EnumerateItems(
 	ctx context.Context,
 	indexName string,
-	query elastic.Query,
-	sorter elastic.Sorter,
+	query Query,
+	sorter Sorter,
 	onItem func(item json.RawMessage, nCurrentItem, nTotalItems int64, commit bool) error,
 ) error {
 
@@ -296,6 +444,10 @@ func (c *ElasticClientImpl) EnsureIndexWithMapping(ctx context.Context, indexNam
 }
 
 func (c *ElasticClientImpl) DoIndex(ctx context.Context, indexName string, data map[string]interface{}) error {
+	if len(data) > bulkIngestThreshold {
+		return ingestViaBulk(ctx, c, indexName, data, BulkIndex)
+	}
+
 	service := c.client.Index().Index(indexName)
 	for idx, dat := range data {
 		_, err := service.Id(idx).BodyJson(&dat).Do(ctx)
@@ -308,6 +460,10 @@ func (c *ElasticClientImpl) DoIndex(ctx context.Context, indexName string, data
 }
 
 func (c *ElasticClientImpl) DoIndexWithNameProvider(ctx context.Context, data map[string]IndexNameProvider) error {
+	if len(data) > bulkIngestThreshold {
+		return ingestViaBulkWithNameProvider(ctx, c, data)
+	}
+
 	service := c.client.Index()
 	for idx, dat := range data {
 		_, err := service.Id(idx).Index(dat.IndexName()).BodyJson(&dat).Do(ctx)
@@ -320,6 +476,10 @@ func (c *ElasticClientImpl) DoIndexWithNameProvider(ctx context.Context, data ma
 }
 
 func (c *ElasticClientImpl) DoCreate(ctx context.Context, indexName string, data map[string]interface{}) error {
+	if len(data) > bulkIngestThreshold {
+		return ingestViaBulk(ctx, c, indexName, data, BulkCreate)
+	}
+
 	service := c.client.Index().Index(indexName).OpType("create")
 	for idx, dat := range data {
 		_, err := service.Id(idx).BodyJson(&dat).Do(ctx)
@@ -332,18 +492,72 @@ func (c *ElasticClientImpl) DoCreate(ctx context.Context, indexName string, data
 }
 
 func (c *ElasticClientImpl) RunBulkProcessor(ctx context.Context, p *BulkProcessorParameters) (
-	*elastic.BulkProcessor, error) {
+	BulkProcessor, error) {
 
-	return c.client.BulkProcessor().
+	builder := c.client.BulkProcessor().
 		Name(p.Name).
 		Workers(p.NumOfWorkers).
 		BulkActions(p.BulkActions).
 		BulkSize(p.BulkSize).
 		FlushInterval(p.FlushInterval).
-		Backoff(p.Backoff).
-		Before(p.BeforeFunc).
-		After(p.AfterFunc).
-		Do(ctx)
+		Backoff(p.Backoff)
+
+	if p.BeforeFunc != nil {
+		before := p.BeforeFunc
+		builder = builder.Before(func(executionId int64, requests []elastic.BulkableRequest) {
+			before(executionId, adaptV7BulkableRequests(requests))
+		})
+	}
+
+	if p.AfterFunc != nil {
+		after := p.AfterFunc
+		builder = builder.After(func(executionId int64, requests []elastic.BulkableRequest, response *elastic.BulkResponse, err error) {
+			after(executionId, adaptV7BulkableRequests(requests), bulkCommitResultFromV7(response), err)
+		})
+	}
+
+	proc, err := builder.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v7BulkProcessor{proc: proc}, nil
+}
+
+// adaptV7BulkableRequests converts olivere's BulkableRequest (which embeds
+// fmt.Stringer on top of the Source() method) to the core package's, so
+// BulkBeforeFunc/BulkAfterFunc stay olivere-free.
+func adaptV7BulkableRequests(requests []elastic.BulkableRequest) []BulkableRequest {
+	out := make([]BulkableRequest, len(requests))
+	for i, r := range requests {
+		out[i] = r
+	}
+	return out
+}
+
+// bulkCommitResultFromV7 reduces an olivere *elastic.BulkResponse down to
+// the backend-neutral BulkCommitResult BulkAfterFunc expects. Returns nil
+// for a nil response, e.g. when the commit itself failed outright.
+func bulkCommitResultFromV7(res *elastic.BulkResponse) *BulkCommitResult {
+	if res == nil {
+		return nil
+	}
+
+	result := &BulkCommitResult{}
+	for _, item := range res.Items {
+		for _, action := range item {
+			if action == nil {
+				continue
+			}
+			if action.Status >= 200 && action.Status < 300 {
+				result.Succeeded++
+			} else {
+				result.Failed++
+			}
+		}
+	}
+
+	return result
 }
 
 // root is for nested object like Attr property for search attributes.
@@ -358,8 +572,73 @@ func (c *ElasticClientImpl) CreateIndex(ctx context.Context, index string) error
 	return err
 }
 
-func (c *ElasticClientImpl) Ping() *elastic.PingService {
-	return c.client.Ping(c.endpoint)
+func (c *ElasticClientImpl) Ping(ctx context.Context) (*PingResult, error) {
+	info, _, err := c.client.Ping(c.endpoint).Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Do")
+	}
+
+	return &PingResult{
+		Name:        info.Name,
+		ClusterName: info.ClusterName,
+		Version:     info.Version.Number,
+		TagLine:     info.TagLine,
+	}, nil
+}
+
+// newSearchResultFromV7 adapts an olivere *elastic.SearchResult into the
+// backend-neutral SearchResult so callers don't depend on olivere types.
+func newSearchResultFromV7(res *elastic.SearchResult) *SearchResult {
+	hits := make([]json.RawMessage, len(res.Hits.Hits))
+	for i, hit := range res.Hits.Hits {
+		hits[i] = hit.Source
+	}
+
+	var aggs json.RawMessage
+	if res.Aggregations != nil {
+		aggs, _ = json.Marshal(res.Aggregations)
+	}
+
+	return &SearchResult{
+		TookMillis:   res.TookInMillis,
+		TotalHits:    res.Hits.TotalHits.Value,
+		MaxScore:     res.Hits.MaxScore,
+		Hits:         hits,
+		ScrollID:     res.ScrollId,
+		Aggregations: aggs,
+	}
+}
+
+// v7BulkProcessor adapts *elastic.BulkProcessor to the backend-neutral
+// BulkProcessor interface.
+type v7BulkProcessor struct {
+	proc *elastic.BulkProcessor
+}
+
+func (p *v7BulkProcessor) Add(request BulkableRequest) {
+	p.proc.Add(request.(elastic.BulkableRequest))
+}
+
+func (p *v7BulkProcessor) Flush() error {
+	return p.proc.Flush()
+}
+
+func (p *v7BulkProcessor) Close() error {
+	return p.proc.Close()
+}
+
+func (p *v7BulkProcessor) Stats() BulkProcessorStats {
+	st := p.proc.Stats()
+	return BulkProcessorStats{
+		Flushed:   st.Flushed,
+		Committed: st.Committed,
+		Indexed:   st.Indexed,
+		Created:   st.Created,
+		Updated:   st.Updated,
+		Deleted:   st.Deleted,
+		Succeeded: st.Succeeded,
+		Failed:    st.Failed,
+	}
 }
 
 func buildPutMappingBody(root, key, valueType string) map[string]interface{} {
@@ -384,27 +663,45 @@ func buildPutMappingBody(root, key, valueType string) map[string]interface{} {
 	return body
 }
 
+// NewClient dials an ES 6/7 cluster using the olivere/v7 driver.
+//
+// Deprecated: use NewClientFromConfig, which also supports TLS/transport
+// overrides and a pluggable retry backoff.
 func NewClient(endpoint, userName, password string, healthCheckInterval time.Duration, sniff bool) (*ElasticClientImpl, error) {
-	client, err := elastic.NewClient(
-		elastic.SetSniff(sniff),
-		elastic.SetURL(endpoint),
-		elastic.SetHealthcheckInterval(healthCheckInterval),
-		elastic.SetBasicAuth(userName, password),
-		elastic.SetRetrier(elastic.NewBackoffRetrier(
-			elastic.NewExponentialBackoff(128*time.Millisecond, 513*time.Millisecond)),
-		),
+	return NewClientFromConfig(Config{
+		Endpoint:            endpoint,
+		Username:            userName,
+		Password:            password,
+		HealthcheckInterval: healthCheckInterval,
+		Sniff:               sniff,
+	})
+}
+
+// NewClientFromConfig dials an ES 6/7 cluster using the olivere/v7 driver.
+func NewClientFromConfig(cfg Config) (*ElasticClientImpl, error) {
+	opts := []elastic.ClientOptionFunc{
+		elastic.SetSniff(cfg.Sniff),
+		elastic.SetURL(cfg.Endpoint),
+		elastic.SetHealthcheckInterval(cfg.HealthcheckInterval),
+		elastic.SetBasicAuth(cfg.Username, cfg.Password),
+		elastic.SetRetrier(elastic.NewBackoffRetrier(cfg.retryBackoff())),
 		// critical to ensure decode of int64 won't lose precision
 		elastic.SetDecoder(&elastic.NumberDecoder{}),
-	)
+	}
+
+	if httpClient := cfg.httpClient(); httpClient != nil {
+		opts = append(opts, elastic.SetHttpClient(httpClient))
+	}
 
+	client, err := elastic.NewClient(opts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "NewClient")
 	}
 
 	return &ElasticClientImpl{
-		endpoint: endpoint,
-		userName: userName,
-		password: password,
+		endpoint: cfg.Endpoint,
+		userName: cfg.Username,
+		password: cfg.Password,
 		client:   client,
 	}, nil
 }
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+)
+
+// Config holds everything needed to dial an Elasticsearch cluster and build
+// an ElasticClient. It replaces the positional-argument constructors that
+// used to take (endpoint, userName, password, healthCheckInterval, sniff).
+type Config struct {
+	Endpoint string
+	Username string
+	Password string
+
+	// Driver pins the backend; DriverAuto (the zero value) detects it from
+	// the cluster's ping response.
+	Driver Driver
+
+	Sniff               bool
+	HealthcheckInterval time.Duration
+
+	// RetryBackoff controls the v7 driver's retry policy. Defaults to the
+	// same exponential backoff NewClient used to hard-code if left nil.
+	RetryBackoff elastic.Backoff
+
+	// TLSConfig is used to build the underlying HTTP client's transport
+	// when Transport isn't set.
+	TLSConfig *tls.Config
+
+	// Transport, if set, overrides the HTTP transport used to talk to the
+	// cluster - e.g. to install tracing or metrics instrumentation.
+	Transport http.RoundTripper
+
+	// Logger receives connection lifecycle messages. Defaults to the
+	// package-level zlog equivalent of the calling package if nil.
+	Logger *zap.Logger
+
+	// Observability, if set, wraps the returned client with OpenTelemetry
+	// spans and Prometheus metrics - both at the ElasticClient method level
+	// (see NewObservableClient) and, via httpClient, at the HTTP transport
+	// level.
+	Observability *Observability
+}
+
+func (cfg Config) httpClient() *http.Client {
+	transport := cfg.Transport
+	if transport == nil && cfg.TLSConfig != nil {
+		transport = &http.Transport{TLSClientConfig: cfg.TLSConfig}
+	}
+
+	if cfg.Observability != nil {
+		transport = newTracingTransport(transport, cfg.Observability)
+	}
+
+	if transport == nil {
+		return nil
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+func (cfg Config) retryBackoff() elastic.Backoff {
+	if cfg.RetryBackoff != nil {
+		return cfg.RetryBackoff
+	}
+
+	return elastic.NewExponentialBackoff(128*time.Millisecond, 513*time.Millisecond)
+}
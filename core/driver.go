@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
+)
+
+// Driver selects which Elasticsearch wire protocol implementation backs an
+// ElasticClient. ES 8 changed its response format enough (typed queries,
+// removal of mapping types, etc.) that a single olivere-based client can no
+// longer talk to both 6/7 and 8 clusters, hence the split.
+type Driver int
+
+const (
+	// DriverAuto probes the cluster on connect and picks DriverV7 or DriverV8
+	// based on the reported version number.
+	DriverAuto Driver = iota
+	// DriverV7 talks to ES 6/7 clusters via github.com/olivere/elastic/v7.
+	DriverV7
+	// DriverV8 talks to ES 8 clusters via github.com/elastic/go-elasticsearch/v8.
+	DriverV8
+)
+
+func (d Driver) String() string {
+	switch d {
+	case DriverV7:
+		return "v7"
+	case DriverV8:
+		return "v8"
+	default:
+		return "auto"
+	}
+}
+
+// ElasticClient is the backend-neutral surface this module exposes. It is
+// implemented by ElasticClientImpl (olivere/v7, for ES 6/7) and
+// ElasticClientV8 (go-elasticsearch/v8, for ES 8). Callers program against
+// this interface and the concrete types underneath, rather than against
+// olivere's elastic.Query/elastic.Sorter/elastic.SearchResult directly, so
+// that switching Driver doesn't ripple through calling code.
+//
+// Several of those concrete types (Query, Sorter, Aggregation,
+// BulkRetryBackoff, ...) are interfaces whose method set happens to match an
+// equivalent olivere type exactly, so existing v7 callers can keep passing
+// elastic query/sort/aggregation/backoff builders straight through without
+// an adapter.
+type ElasticClient interface {
+	Bulk(ctx context.Context, items []BulkItem) (*BulkResult, error)
+	CancelTask(ctx context.Context, id TaskID) error
+	ClearScroll(ctx context.Context, scrollID string) error
+	Count(ctx context.Context, index string, query string) (int64, error)
+	CreateIndex(ctx context.Context, index string) error
+	DeleteByQuery(ctx context.Context, index string, query Query) (TaskID, error)
+	DoCreate(ctx context.Context, indexName string, data map[string]interface{}) error
+	DoIndex(ctx context.Context, indexName string, data map[string]interface{}) error
+	DoIndexWithNameProvider(ctx context.Context, data map[string]IndexNameProvider) error
+	EnsureIndexWithMapping(ctx context.Context, indexName string, mapping string) error
+	EnumerateItems(ctx context.Context, indexName string, query Query, sorter Sorter, onItem func(item json.RawMessage, nCurrentItem int64, nTotalItems int64, commit bool) error) error
+	FlushIndex(ctx context.Context, index string) error
+	GetTask(ctx context.Context, id TaskID) (*TaskStatus, error)
+	GetIndices(prefixes []string) (map[string][]string, error)
+	GetIndicesInfo(ctx context.Context, opts GetIndicesOptions) ([]IndexInfo, error)
+	MarshalWithNameAndIDProvider(ctx context.Context, data IndexNameAndIDProvider) error
+	OpenPIT(ctx context.Context, index string, keepAlive time.Duration) (string, error)
+	ClosePIT(ctx context.Context, id string) error
+	Ping(ctx context.Context) (*PingResult, error)
+	PutMapping(ctx context.Context, index string, root string, key string, valueType string) error
+	Reindex(ctx context.Context, p *ReindexParams) (TaskID, error)
+	RunBulkProcessor(ctx context.Context, p *BulkProcessorParameters) (BulkProcessor, error)
+	Search(ctx context.Context, p *SearchParameters) (*SearchResult, error)
+	SearchAfterPage(ctx context.Context, p *SearchAfterParameters) (*SearchAfterResult, error)
+	SearchWithDSL(ctx context.Context, index string, query string) (*SearchResult, error)
+	UnmarshalMostRecent(ctx context.Context, indexName string, query Query, timestampField string, target interface{}) error
+	UnmarshalOne(ctx context.Context, indexName string, query Query, target interface{}) error
+	UpdateByQuery(ctx context.Context, index string, query Query, script string) (TaskID, error)
+}
+
+// DetectDriver pings cfg.Endpoint and picks the Driver matching the
+// reported cluster version. It is used by Get/New when the caller didn't
+// pin an explicit Driver. It dials through cfg.httpClient() so a custom
+// TLSConfig/Transport applies to the detection ping the same way it does
+// to the driver New() constructs afterward.
+func DetectDriver(ctx context.Context, cfg Config) (Driver, error) {
+	opts := []elastic.ClientOptionFunc{
+		elastic.SetURL(cfg.Endpoint),
+		elastic.SetBasicAuth(cfg.Username, cfg.Password),
+	}
+
+	if httpClient := cfg.httpClient(); httpClient != nil {
+		opts = append(opts, elastic.SetHttpClient(httpClient))
+	}
+
+	client, err := elastic.NewSimpleClient(opts...)
+	if err != nil {
+		return DriverAuto, errors.Wrap(err, "NewSimpleClient")
+	}
+
+	info, _, err := client.Ping(cfg.Endpoint).Do(ctx)
+	if err != nil {
+		return DriverAuto, errors.Wrap(err, "Ping")
+	}
+
+	if strings.HasPrefix(info.Version.Number, "8.") {
+		return DriverV8, nil
+	}
+
+	return DriverV7, nil
+}
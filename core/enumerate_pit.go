@@ -0,0 +1,237 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+// defaultPITKeepAlive is how long EnumerateItemsPIT/EnumerateItemsParallel
+// keep their point in time alive between pages.
+const defaultPITKeepAlive = 2 * time.Minute
+
+// pitPageSize mirrors the scroll batch size the v8 driver's EnumerateItems
+// uses; the v7 driver's scroll sets no size of its own.
+const pitPageSize = 1000
+
+// tiebreakerSorter breaks ties on the caller's sort so search_after paging
+// never skips or repeats a document. _shard_doc is Elasticsearch's own
+// per-shard Lucene doc order and is only valid inside a PIT search.
+var tiebreakerSorter Sorter = RawSorter(`{"_shard_doc":"asc"}`)
+
+// SliceSpec partitions a PIT search into one of Max independent slices, so
+// EnumerateItemsParallel's goroutines each see a disjoint subset of matches.
+type SliceSpec struct {
+	ID  int
+	Max int
+}
+
+// SearchAfterParameters holds the parameters for a single page of a
+// point-in-time + search_after search. Unlike SearchParameters, it targets a
+// PIT rather than an index name, since the PIT already pins the index(es) it
+// was opened against.
+type SearchAfterParameters struct {
+	PitID       string
+	Query       Query
+	Sorter      []Sorter
+	SearchAfter []interface{}
+	PageSize    int
+	Slice       *SliceSpec
+}
+
+// SearchAfterResult is a single page of a point-in-time + search_after
+// search. LastSort carries the sort values of the last hit, to be fed back
+// in as the next page's SearchAfter.
+type SearchAfterResult struct {
+	Hits      []json.RawMessage
+	TotalHits int64
+	LastSort  []interface{}
+}
+
+// EnumerateItemsPIT streams every document matching query out of indexName
+// using a point in time and search_after paging, instead of the scroll API
+// EnumerateItems relies on. Scroll holds an expensive, deprecated server-side
+// context; PIT is the replacement Elasticsearch recommends for exhaustive,
+// non-paginated-by-the-user scans. A tiebreaker sort on _shard_doc is always
+// appended after sorters so paging is stable even when the caller's sort
+// field has ties; onItem matches EnumerateItems' callback signature.
+func EnumerateItemsPIT(
+	ctx context.Context,
+	client ElasticClient,
+	indexName string,
+	query Query,
+	sorters []Sorter,
+	onItem func(item json.RawMessage, nCurrentItem, nTotalItems int64, commit bool) error,
+) error {
+
+	sorters = append(append([]Sorter{}, sorters...), tiebreakerSorter)
+
+	pitID, err := client.OpenPIT(ctx, indexName, defaultPITKeepAlive)
+	if err != nil {
+		return errors.Wrap(err, "OpenPIT")
+	}
+
+	errs := new(multierror.Error)
+	var (
+		nCurrentItem int64
+		searchAfter  []interface{}
+	)
+
+	for len(errs.Errors) == 0 {
+		page, err := client.SearchAfterPage(ctx, &SearchAfterParameters{
+			PitID:       pitID,
+			Query:       query,
+			Sorter:      sorters,
+			SearchAfter: searchAfter,
+			PageSize:    pitPageSize,
+		})
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrap(err, "SearchAfterPage"))
+			break
+		}
+
+		nBatchItems := len(page.Hits)
+		if nBatchItems == 0 {
+			break
+		}
+
+		for idx, hit := range page.Hits {
+			nCurrentItem++
+			commit := idx == nBatchItems-1
+			if err := onItem(hit, nCurrentItem, page.TotalHits, commit); err != nil {
+				errs = multierror.Append(errs, errors.Wrap(err, "onItem"))
+				break
+			}
+		}
+
+		if len(errs.Errors) != 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			errs = multierror.Append(errs, ctx.Err())
+		default:
+		}
+
+		searchAfter = page.LastSort
+	}
+
+	if err := client.ClosePIT(ctx, pitID); err != nil {
+		errs = multierror.Append(errs, errors.Wrap(err, "ClosePIT"))
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// EnumerateItemsParallel is EnumerateItemsPIT fanned out across numSlices
+// goroutines via PIT slicing, so a full-index scan can saturate a
+// multi-shard cluster instead of pulling pages through a single scroll/PIT
+// cursor. All slices share one PIT and one merged nCurrentItem counter;
+// onItem matches EnumerateItems' callback signature, so commit still only
+// fires on the last item of each slice's own page.
+//
+// Unlike EnumerateItems and EnumerateItemsPIT, which call onItem
+// sequentially, EnumerateItemsParallel invokes it concurrently from up to
+// numSlices goroutines whenever numSlices >= 2 - onItem must be safe for
+// concurrent use in that case.
+func EnumerateItemsParallel(
+	ctx context.Context,
+	client ElasticClient,
+	indexName string,
+	query Query,
+	sorter Sorter,
+	numSlices int,
+	onItem func(item json.RawMessage, nCurrentItem, nTotalItems int64, commit bool) error,
+) error {
+
+	if numSlices < 2 {
+		var sorters []Sorter
+		if sorter != nil {
+			sorters = []Sorter{sorter}
+		}
+		return EnumerateItemsPIT(ctx, client, indexName, query, sorters, onItem)
+	}
+
+	sorters := []Sorter{tiebreakerSorter}
+	if sorter != nil {
+		sorters = []Sorter{sorter, tiebreakerSorter}
+	}
+
+	pitID, err := client.OpenPIT(ctx, indexName, defaultPITKeepAlive)
+	if err != nil {
+		return errors.Wrap(err, "OpenPIT")
+	}
+
+	var (
+		nCurrentItem int64
+		wg           sync.WaitGroup
+		mu           sync.Mutex
+		errs         = new(multierror.Error)
+	)
+
+	appendErr := func(err error) {
+		mu.Lock()
+		errs = multierror.Append(errs, err)
+		mu.Unlock()
+	}
+
+	for slice := 0; slice < numSlices; slice++ {
+		wg.Add(1)
+		go func(sliceID int) {
+			defer wg.Done()
+
+			var searchAfter []interface{}
+			for {
+				page, err := client.SearchAfterPage(ctx, &SearchAfterParameters{
+					PitID:       pitID,
+					Query:       query,
+					Sorter:      sorters,
+					SearchAfter: searchAfter,
+					PageSize:    pitPageSize,
+					Slice:       &SliceSpec{ID: sliceID, Max: numSlices},
+				})
+				if err != nil {
+					appendErr(errors.Wrap(err, "SearchAfterPage"))
+					return
+				}
+
+				nBatchItems := len(page.Hits)
+				if nBatchItems == 0 {
+					return
+				}
+
+				for idx, hit := range page.Hits {
+					n := atomic.AddInt64(&nCurrentItem, 1)
+					commit := idx == nBatchItems-1
+					if err := onItem(hit, n, page.TotalHits, commit); err != nil {
+						appendErr(errors.Wrap(err, "onItem"))
+						return
+					}
+				}
+
+				select {
+				case <-ctx.Done():
+					appendErr(ctx.Err())
+					return
+				default:
+				}
+
+				searchAfter = page.LastSort
+			}
+		}(slice)
+	}
+
+	wg.Wait()
+
+	if err := client.ClosePIT(ctx, pitID); err != nil {
+		appendErr(errors.Wrap(err, "ClosePIT"))
+	}
+
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeEnumerateClient embeds ElasticClient so it satisfies the interface
+// while only implementing the three methods EnumerateItemsPIT/
+// EnumerateItemsParallel actually call.
+type fakeEnumerateClient struct {
+	ElasticClient
+	sorters [][]Sorter
+}
+
+func (f *fakeEnumerateClient) OpenPIT(ctx context.Context, index string, keepAlive time.Duration) (string, error) {
+	return "", nil
+}
+
+func (f *fakeEnumerateClient) ClosePIT(ctx context.Context, id string) error {
+	return nil
+}
+
+func (f *fakeEnumerateClient) SearchAfterPage(ctx context.Context, p *SearchAfterParameters) (*SearchAfterResult, error) {
+	f.sorters = append(f.sorters, p.Sorter)
+	return &SearchAfterResult{}, nil
+}
+
+func TestEnumerateItemsPITAppendsTiebreaker(t *testing.T) {
+	userSorter := RawSorter(`{"created_at":"asc"}`)
+
+	for _, tc := range []struct {
+		name    string
+		sorters []Sorter
+	}{
+		{"no caller sorter", nil},
+		{"one caller sorter", []Sorter{userSorter}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakeEnumerateClient{}
+			err := EnumerateItemsPIT(context.Background(), client, "idx", nil, tc.sorters,
+				func(item json.RawMessage, nCurrentItem, nTotalItems int64, commit bool) error { return nil })
+			if err != nil {
+				t.Fatalf("EnumerateItemsPIT: %v", err)
+			}
+
+			if len(client.sorters) != 1 {
+				t.Fatalf("SearchAfterPage called %d times, want 1", len(client.sorters))
+			}
+
+			got := client.sorters[0]
+			if len(got) == 0 {
+				t.Fatalf("sorters = %+v, want at least the tiebreaker", got)
+			}
+			last, err := got[len(got)-1].Source()
+			if err != nil {
+				t.Fatalf("last sorter Source(): %v", err)
+			}
+			want, _ := tiebreakerSorter.Source()
+			if !reflect.DeepEqual(last, want) {
+				t.Errorf("last sorter = %+v, want tiebreakerSorter %+v", last, want)
+			}
+			if len(got) != len(tc.sorters)+1 {
+				t.Errorf("len(sorters) = %d, want %d (caller sorters + tiebreaker)", len(got), len(tc.sorters)+1)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package core
+
+import "strconv"
+
+// GetIndicesOptions controls GetIndicesInfo. Prefixes narrows the result to
+// indices whose name starts with one of the given strings (matched with a
+// "<prefix>*" wildcard, not the old regex's "<prefix>-<digits>" shape); a
+// nil/empty Prefixes lists every index.
+type GetIndicesOptions struct {
+	Prefixes []string
+
+	// IncludeAliases adds one IndexInfo entry per (index, alias) pair an
+	// index belongs to, on top of the index's own unaliased entry.
+	IncludeAliases bool
+
+	// IncludeHidden also matches dot-prefixed indices, which /_cat/indices
+	// excludes by default.
+	IncludeHidden bool
+
+	// SortBy is passed through to the Cat Indices API's own "s" sort
+	// parameter, e.g. []string{"index"} or []string{"docs.count:desc"}.
+	SortBy []string
+}
+
+// IndexInfo describes a single index, or a single (index, alias) pair when
+// GetIndicesOptions.IncludeAliases is set.
+type IndexInfo struct {
+	Name           string
+	Alias          string
+	IsWriteIndex   bool
+	DocCount       int64
+	StoreSizeBytes int64
+	Health         string
+	CreationDate   int64
+}
+
+// buildIndexPatterns turns GetIndicesOptions.Prefixes/IncludeHidden into the
+// comma-separated index pattern both drivers' Cat Indices/Aliases calls take.
+// An empty return means "every index".
+func buildIndexPatterns(prefixes []string, includeHidden bool) []string {
+	if len(prefixes) == 0 {
+		if includeHidden {
+			return []string{"*", ".*"}
+		}
+		return nil
+	}
+
+	patterns := make([]string, 0, len(prefixes)*2)
+	for _, prefix := range prefixes {
+		patterns = append(patterns, prefix+"*")
+		if includeHidden {
+			patterns = append(patterns, "."+prefix+"*")
+		}
+	}
+
+	return patterns
+}
+
+// parseByteCount parses a Cat Indices store.size value requested with
+// Bytes("b"), which renders as a plain integer instead of a humanized
+// string like "4.6kb". Returns 0 if the value can't be parsed, e.g. "-" for
+// an index with no data yet.
+func parseByteCount(s string) int64 {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// catAliasRow is the backend-neutral shape both drivers reduce their Cat
+// Aliases response row to, so expandWithAliases doesn't need to know
+// whether it came from olivere's CatAliasesResponseRow or v8's raw JSON.
+type catAliasRow struct {
+	Index        string
+	Alias        string
+	IsWriteIndex bool
+}
+
+// expandWithAliases appends one IndexInfo per (index, alias) pair in rows
+// to base, copying the matching index's other fields onto each. Indices
+// with no aliases, or not present in rows, are left with just their base
+// entry.
+func expandWithAliases(base []IndexInfo, rows []catAliasRow) []IndexInfo {
+	byIndex := make(map[string][]catAliasRow, len(rows))
+	for _, row := range rows {
+		byIndex[row.Index] = append(byIndex[row.Index], row)
+	}
+
+	result := make([]IndexInfo, 0, len(base))
+	for _, info := range base {
+		result = append(result, info)
+
+		for _, row := range byIndex[info.Name] {
+			aliased := info
+			aliased.Alias = row.Alias
+			aliased.IsWriteIndex = row.IsWriteIndex
+			result = append(result, aliased)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,247 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
+)
+
+// BulkIngesterParameters holds all required and optional parameters for
+// running a BulkIngester.
+type BulkIngesterParameters struct {
+	Name          string
+	BatchSize     int           // flush once this many items are buffered
+	BatchBytes    int           // flush once the buffered NDJSON body reaches this many bytes
+	FlushInterval time.Duration // flush on a timer even if thresholds aren't hit
+	MaxRetries    int           // per-item retry attempts on failure
+	Backoff       elastic.Backoff
+	// OnItem, if set, is called once per submitted item after each flush
+	// that includes it, with the outcome of its last attempt. Items of a
+	// Bulk call that fails as a whole are counted as Failed but not passed
+	// to OnItem, nor are items still waiting for a retry when ctx is done.
+	OnItem func(item BulkItem, result BulkItemResult)
+}
+
+// BulkIngesterStats reports cumulative BulkIngester counters.
+type BulkIngesterStats struct {
+	Indexed int64
+	Failed  int64
+	Bytes   int64
+	Flushes int64
+}
+
+// BulkIngester batches Index/Create/Update/Delete actions and flushes them
+// to ElasticClient.Bulk on a count/size/interval basis, retrying individual
+// failed items (not the whole batch) with the configured backoff. Unlike
+// RunBulkProcessor it doesn't round-trip through the driver's own bulk
+// processor, so it works identically against either driver and can report
+// granular per-item outcomes.
+type BulkIngester struct {
+	client ElasticClient
+	params *BulkIngesterParameters
+
+	mu  sync.Mutex
+	buf []BulkItem
+
+	statsMu sync.Mutex
+	stats   BulkIngesterStats
+
+	wg      sync.WaitGroup
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewBulkIngester starts a BulkIngester. Callers must Close it to flush any
+// remaining buffered items and stop the flush-interval goroutine.
+func NewBulkIngester(ctx context.Context, client ElasticClient, p *BulkIngesterParameters) *BulkIngester {
+	bi := &BulkIngester{
+		client:  client,
+		params:  p,
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+
+	if p.FlushInterval > 0 {
+		go bi.flushLoop(ctx, p.FlushInterval)
+	} else {
+		close(bi.doneCh)
+	}
+
+	return bi
+}
+
+func (bi *BulkIngester) flushLoop(ctx context.Context, interval time.Duration) {
+	defer close(bi.doneCh)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-bi.closeCh:
+			return
+		case <-ticker.C:
+			_ = bi.Flush(ctx)
+		}
+	}
+}
+
+// Add buffers a single document action, flushing immediately if it crosses
+// the configured batch thresholds.
+func (bi *BulkIngester) Add(ctx context.Context, item BulkItem) error {
+	bi.mu.Lock()
+	bi.buf = append(bi.buf, item)
+	n := len(bi.buf)
+	bi.mu.Unlock()
+
+	shouldFlush := (bi.params.BatchSize > 0 && n >= bi.params.BatchSize)
+	if !shouldFlush && bi.params.BatchBytes > 0 {
+		body, err := bi.peekBufBytes()
+		if err == nil && len(body) >= bi.params.BatchBytes {
+			shouldFlush = true
+		}
+	}
+
+	if shouldFlush {
+		return bi.Flush(ctx)
+	}
+
+	return nil
+}
+
+// AddDoc buffers an Index or Create action built from an
+// IndexNameAndIDProvider, the convention the rest of this package uses to
+// carry a document's destination index and ID alongside its body.
+func (bi *BulkIngester) AddDoc(ctx context.Context, action BulkActionType, doc IndexNameAndIDProvider) error {
+	return bi.Add(ctx, BulkItem{
+		Action: action,
+		Index:  doc.IndexName(),
+		ID:     doc.ID(),
+		Doc:    doc,
+	})
+}
+
+func (bi *BulkIngester) peekBufBytes() ([]byte, error) {
+	bi.mu.Lock()
+	defer bi.mu.Unlock()
+	return buildBulkBody(bi.buf)
+}
+
+// Flush sends any buffered items now, retrying individually failed items up
+// to MaxRetries with the configured Backoff before giving up on them.
+func (bi *BulkIngester) Flush(ctx context.Context) error {
+	bi.mu.Lock()
+	items := bi.buf
+	bi.buf = nil
+	bi.mu.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	return bi.sendWithRetry(ctx, items, 0)
+}
+
+func (bi *BulkIngester) sendWithRetry(ctx context.Context, items []BulkItem, attempt int) error {
+	res, err := bi.client.Bulk(ctx, items)
+	if err != nil {
+		bi.statsMu.Lock()
+		bi.stats.Failed += int64(len(items))
+		bi.statsMu.Unlock()
+		return err
+	}
+
+	bi.statsMu.Lock()
+	bi.stats.Flushes++
+	bi.stats.Bytes += int64(res.BytesInBody)
+	bi.statsMu.Unlock()
+
+	if len(res.Items) != len(items) {
+		bi.statsMu.Lock()
+		bi.stats.Failed += int64(len(items))
+		bi.statsMu.Unlock()
+		return errors.Errorf("bulk response item count %d does not match request count %d", len(res.Items), len(items))
+	}
+
+	var retryItems []BulkItem
+	var retryResults []BulkItemResult
+	for i, item := range items {
+		result := res.Items[i]
+		if result.Error == nil {
+			bi.statsMu.Lock()
+			bi.stats.Indexed++
+			bi.statsMu.Unlock()
+			bi.notify(item, result)
+			continue
+		}
+
+		if attempt < bi.params.MaxRetries {
+			retryItems = append(retryItems, item)
+			retryResults = append(retryResults, result)
+			continue
+		}
+
+		bi.statsMu.Lock()
+		bi.stats.Failed++
+		bi.statsMu.Unlock()
+		bi.notify(item, result)
+	}
+
+	if len(retryItems) == 0 {
+		return nil
+	}
+
+	if bi.params.Backoff != nil {
+		wait, ok := bi.params.Backoff.Next(attempt)
+		if !ok {
+			bi.statsMu.Lock()
+			bi.stats.Failed += int64(len(retryItems))
+			bi.statsMu.Unlock()
+			for i, item := range retryItems {
+				bi.notify(item, retryResults[i])
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+
+	return bi.sendWithRetry(ctx, retryItems, attempt+1)
+}
+
+func (bi *BulkIngester) notify(item BulkItem, result BulkItemResult) {
+	if bi.params.OnItem != nil {
+		bi.params.OnItem(item, result)
+	}
+}
+
+// Stats returns a snapshot of cumulative counters.
+func (bi *BulkIngester) Stats() BulkIngesterStats {
+	bi.statsMu.Lock()
+	defer bi.statsMu.Unlock()
+	return bi.stats
+}
+
+// Close flushes any remaining buffered items, draining in-flight work before
+// returning, and stops the flush-interval goroutine if one is running.
+func (bi *BulkIngester) Close(ctx context.Context) error {
+	err := bi.Flush(ctx)
+
+	select {
+	case <-bi.closeCh:
+	default:
+		close(bi.closeCh)
+	}
+	<-bi.doneCh
+
+	return err
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/denkhaus/es"
+
+// Observability wires an ElasticClient up to OpenTelemetry tracing and
+// Prometheus metrics. Both fields are optional: a nil TracerProvider falls
+// back to the global otel TracerProvider, and a nil Registerer disables
+// metrics registration while tracing still works.
+type Observability struct {
+	TracerProvider trace.TracerProvider
+	Registerer     prometheus.Registerer
+}
+
+func (o *Observability) tracer() trace.Tracer {
+	tp := o.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(tracerName)
+}
+
+// metrics are the Prometheus instruments observableClient records against.
+// tracingTransport only opens spans and records no metrics.
+type metrics struct {
+	requestDuration  *prometheus.HistogramVec
+	bulkItemFailures *prometheus.CounterVec
+	openContexts     prometheus.Gauge
+}
+
+var (
+	metricsMu    sync.Mutex
+	metricsByReg = make(map[prometheus.Registerer]*metrics)
+)
+
+// newMetrics returns the *metrics registered against reg, registering them
+// on first use and reusing that same instance on every later call. New()
+// can be called repeatedly to build multiple clients in one process, and
+// callers reasonably pass the same process-wide Registerer (e.g.
+// prometheus.DefaultRegisterer) to more than one of those calls; without
+// this memoization, the second call's MustRegister would panic with a
+// duplicate-registration error.
+func newMetrics(reg prometheus.Registerer) *metrics {
+	if reg == nil {
+		return buildMetrics()
+	}
+
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	if m, ok := metricsByReg[reg]; ok {
+		return m
+	}
+
+	m := buildMetrics()
+	reg.MustRegister(m.requestDuration, m.bulkItemFailures, m.openContexts)
+	metricsByReg[reg] = m
+
+	return m
+}
+
+func buildMetrics() *metrics {
+	return &metrics{
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "elasticsearch",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of ElasticClient operations, by operation and index.",
+		}, []string{"operation", "index"}),
+		bulkItemFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "elasticsearch",
+			Name:      "bulk_item_failures_total",
+			Help:      "Number of bulk items that failed, by response status.",
+		}, []string{"status"}),
+		openContexts: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "elasticsearch",
+			Name:      "open_scroll_contexts",
+			Help:      "Number of scroll/PIT contexts currently open.",
+		}),
+	}
+}
+
+// tracingTransport wraps an http.RoundTripper so every HTTP call to
+// Elasticsearch gets its own span - not just the top-level ElasticClient
+// methods observableClient instruments, several of which (EnumerateItems,
+// RunBulkProcessor) issue more than one HTTP request per call.
+type tracingTransport struct {
+	next   http.RoundTripper
+	tracer trace.Tracer
+}
+
+func newTracingTransport(next http.RoundTripper, obs *Observability) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &tracingTransport{next: next, tracer: obs.tracer()}
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.tracer.Start(req.Context(), "elasticsearch.http",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "elasticsearch"),
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	res, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", res.StatusCode))
+	if res.StatusCode >= 400 {
+		span.SetStatus(codes.Error, res.Status)
+	}
+
+	return res, nil
+}
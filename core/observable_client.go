@@ -0,0 +1,313 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// observableClient decorates an ElasticClient with OpenTelemetry spans and
+// Prometheus metrics around every method, on top of the per-HTTP-call
+// tracing a Config.Observability-backed transport already provides (see
+// tracingTransport). It implements ElasticClient itself, so it's a drop-in
+// wherever the undecorated client was used.
+type observableClient struct {
+	inner   ElasticClient
+	tracer  trace.Tracer
+	metrics *metrics
+}
+
+// NewObservableClient wraps client so every ElasticClient method opens a
+// span and records its duration to Prometheus. Returns client unchanged if
+// obs is nil.
+func NewObservableClient(client ElasticClient, obs *Observability) ElasticClient {
+	if obs == nil {
+		return client
+	}
+
+	return &observableClient{
+		inner:   client,
+		tracer:  obs.tracer(),
+		metrics: newMetrics(obs.Registerer),
+	}
+}
+
+func (c *observableClient) span(ctx context.Context, operation, index string) (context.Context, trace.Span, time.Time) {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "elasticsearch"),
+		attribute.String("db.operation", operation),
+	}
+	if index != "" {
+		attrs = append(attrs, attribute.String("elasticsearch.index", index))
+	}
+
+	ctx, span := c.tracer.Start(ctx, "elasticsearch."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, span, time.Now()
+}
+
+func (c *observableClient) finish(span trace.Span, operation, index string, start time.Time, err error) {
+	c.metrics.requestDuration.WithLabelValues(operation, index).Observe(time.Since(start).Seconds())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (c *observableClient) Bulk(ctx context.Context, items []BulkItem) (*BulkResult, error) {
+	ctx, span, start := c.span(ctx, "bulk", "")
+	res, err := c.inner.Bulk(ctx, items)
+	if err == nil && res != nil {
+		span.SetAttributes(attribute.Int("elasticsearch.item_count", len(res.Items)))
+		for _, item := range res.Items {
+			if item.Status >= 400 {
+				c.metrics.bulkItemFailures.WithLabelValues(strconv.Itoa(item.Status)).Inc()
+			}
+		}
+	}
+	c.finish(span, "bulk", "", start, err)
+	return res, err
+}
+
+func (c *observableClient) CancelTask(ctx context.Context, id TaskID) error {
+	ctx, span, start := c.span(ctx, "cancel_task", "")
+	err := c.inner.CancelTask(ctx, id)
+	c.finish(span, "cancel_task", "", start, err)
+	return err
+}
+
+func (c *observableClient) ClearScroll(ctx context.Context, scrollID string) error {
+	ctx, span, start := c.span(ctx, "clear_scroll", "")
+	err := c.inner.ClearScroll(ctx, scrollID)
+	c.finish(span, "clear_scroll", "", start, err)
+	return err
+}
+
+func (c *observableClient) Count(ctx context.Context, index string, query string) (int64, error) {
+	ctx, span, start := c.span(ctx, "count", index)
+	n, err := c.inner.Count(ctx, index, query)
+	c.finish(span, "count", index, start, err)
+	return n, err
+}
+
+func (c *observableClient) CreateIndex(ctx context.Context, index string) error {
+	ctx, span, start := c.span(ctx, "create_index", index)
+	err := c.inner.CreateIndex(ctx, index)
+	c.finish(span, "create_index", index, start, err)
+	return err
+}
+
+func (c *observableClient) DeleteByQuery(ctx context.Context, index string, query Query) (TaskID, error) {
+	ctx, span, start := c.span(ctx, "delete_by_query", index)
+	id, err := c.inner.DeleteByQuery(ctx, index, query)
+	c.finish(span, "delete_by_query", index, start, err)
+	return id, err
+}
+
+func (c *observableClient) DoCreate(ctx context.Context, indexName string, data map[string]interface{}) error {
+	ctx, span, start := c.span(ctx, "do_create", indexName)
+	err := c.inner.DoCreate(ctx, indexName, data)
+	c.finish(span, "do_create", indexName, start, err)
+	return err
+}
+
+func (c *observableClient) DoIndex(ctx context.Context, indexName string, data map[string]interface{}) error {
+	ctx, span, start := c.span(ctx, "do_index", indexName)
+	err := c.inner.DoIndex(ctx, indexName, data)
+	c.finish(span, "do_index", indexName, start, err)
+	return err
+}
+
+func (c *observableClient) DoIndexWithNameProvider(ctx context.Context, data map[string]IndexNameProvider) error {
+	ctx, span, start := c.span(ctx, "do_index_with_name_provider", "")
+	err := c.inner.DoIndexWithNameProvider(ctx, data)
+	c.finish(span, "do_index_with_name_provider", "", start, err)
+	return err
+}
+
+func (c *observableClient) EnsureIndexWithMapping(ctx context.Context, indexName string, mapping string) error {
+	ctx, span, start := c.span(ctx, "ensure_index_with_mapping", indexName)
+	err := c.inner.EnsureIndexWithMapping(ctx, indexName, mapping)
+	c.finish(span, "ensure_index_with_mapping", indexName, start, err)
+	return err
+}
+
+func (c *observableClient) EnumerateItems(
+	ctx context.Context,
+	indexName string,
+	query Query,
+	sorter Sorter,
+	onItem func(item json.RawMessage, nCurrentItem int64, nTotalItems int64, commit bool) error,
+) error {
+
+	ctx, span, start := c.span(ctx, "enumerate_items", indexName)
+	c.metrics.openContexts.Inc()
+	err := c.inner.EnumerateItems(ctx, indexName, query, sorter, onItem)
+	c.metrics.openContexts.Dec()
+	c.finish(span, "enumerate_items", indexName, start, err)
+	return err
+}
+
+func (c *observableClient) FlushIndex(ctx context.Context, index string) error {
+	ctx, span, start := c.span(ctx, "flush_index", index)
+	err := c.inner.FlushIndex(ctx, index)
+	c.finish(span, "flush_index", index, start, err)
+	return err
+}
+
+func (c *observableClient) GetTask(ctx context.Context, id TaskID) (*TaskStatus, error) {
+	ctx, span, start := c.span(ctx, "get_task", "")
+	status, err := c.inner.GetTask(ctx, id)
+	c.finish(span, "get_task", "", start, err)
+	return status, err
+}
+
+func (c *observableClient) GetIndices(prefixes []string) (map[string][]string, error) {
+	_, span, start := c.span(context.Background(), "get_indices", "")
+	indices, err := c.inner.GetIndices(prefixes)
+	c.finish(span, "get_indices", "", start, err)
+	return indices, err
+}
+
+func (c *observableClient) GetIndicesInfo(ctx context.Context, opts GetIndicesOptions) ([]IndexInfo, error) {
+	ctx, span, start := c.span(ctx, "get_indices_info", "")
+	infos, err := c.inner.GetIndicesInfo(ctx, opts)
+	c.finish(span, "get_indices_info", "", start, err)
+	return infos, err
+}
+
+func (c *observableClient) MarshalWithNameAndIDProvider(ctx context.Context, data IndexNameAndIDProvider) error {
+	ctx, span, start := c.span(ctx, "marshal_with_name_and_id_provider", data.IndexName())
+	err := c.inner.MarshalWithNameAndIDProvider(ctx, data)
+	c.finish(span, "marshal_with_name_and_id_provider", data.IndexName(), start, err)
+	return err
+}
+
+func (c *observableClient) OpenPIT(ctx context.Context, index string, keepAlive time.Duration) (string, error) {
+	ctx, span, start := c.span(ctx, "open_pit", index)
+	id, err := c.inner.OpenPIT(ctx, index, keepAlive)
+	if err == nil {
+		c.metrics.openContexts.Inc()
+	}
+	c.finish(span, "open_pit", index, start, err)
+	return id, err
+}
+
+func (c *observableClient) ClosePIT(ctx context.Context, id string) error {
+	ctx, span, start := c.span(ctx, "close_pit", "")
+	err := c.inner.ClosePIT(ctx, id)
+	c.metrics.openContexts.Dec()
+	c.finish(span, "close_pit", "", start, err)
+	return err
+}
+
+func (c *observableClient) Ping(ctx context.Context) (*PingResult, error) {
+	ctx, span, start := c.span(ctx, "ping", "")
+	res, err := c.inner.Ping(ctx)
+	c.finish(span, "ping", "", start, err)
+	return res, err
+}
+
+func (c *observableClient) PutMapping(ctx context.Context, index string, root string, key string, valueType string) error {
+	ctx, span, start := c.span(ctx, "put_mapping", index)
+	err := c.inner.PutMapping(ctx, index, root, key, valueType)
+	c.finish(span, "put_mapping", index, start, err)
+	return err
+}
+
+func (c *observableClient) Reindex(ctx context.Context, p *ReindexParams) (TaskID, error) {
+	ctx, span, start := c.span(ctx, "reindex", p.DestIndex)
+	id, err := c.inner.Reindex(ctx, p)
+	c.finish(span, "reindex", p.DestIndex, start, err)
+	return id, err
+}
+
+func (c *observableClient) RunBulkProcessor(ctx context.Context, p *BulkProcessorParameters) (BulkProcessor, error) {
+	ctx, span, start := c.span(ctx, "run_bulk_processor", "")
+	proc, err := c.inner.RunBulkProcessor(ctx, p)
+	c.finish(span, "run_bulk_processor", "", start, err)
+	return proc, err
+}
+
+func (c *observableClient) Search(ctx context.Context, p *SearchParameters) (*SearchResult, error) {
+	ctx, span, start := c.span(ctx, "search", p.Index)
+	res, err := c.inner.Search(ctx, p)
+	if err == nil && res != nil {
+		annotateSearchResult(span, p.Query, res)
+	}
+	c.finish(span, "search", p.Index, start, err)
+	return res, err
+}
+
+func (c *observableClient) SearchAfterPage(ctx context.Context, p *SearchAfterParameters) (*SearchAfterResult, error) {
+	ctx, span, start := c.span(ctx, "search_after_page", "")
+	res, err := c.inner.SearchAfterPage(ctx, p)
+	if err == nil && res != nil {
+		span.SetAttributes(attribute.Int("elasticsearch.hit_count", len(res.Hits)))
+	}
+	c.finish(span, "search_after_page", "", start, err)
+	return res, err
+}
+
+func (c *observableClient) SearchWithDSL(ctx context.Context, index string, query string) (*SearchResult, error) {
+	ctx, span, start := c.span(ctx, "search_with_dsl", index)
+	res, err := c.inner.SearchWithDSL(ctx, index, query)
+	if err == nil && res != nil {
+		span.SetAttributes(
+			attribute.Int("elasticsearch.query_body_bytes", len(query)),
+			attribute.Int("elasticsearch.hit_count", len(res.Hits)),
+			attribute.Int64("elasticsearch.took_ms", res.TookMillis),
+		)
+	}
+	c.finish(span, "search_with_dsl", index, start, err)
+	return res, err
+}
+
+func (c *observableClient) UnmarshalMostRecent(ctx context.Context, indexName string, query Query, timestampField string, target interface{}) error {
+	ctx, span, start := c.span(ctx, "unmarshal_most_recent", indexName)
+	err := c.inner.UnmarshalMostRecent(ctx, indexName, query, timestampField, target)
+	c.finish(span, "unmarshal_most_recent", indexName, start, err)
+	return err
+}
+
+func (c *observableClient) UnmarshalOne(ctx context.Context, indexName string, query Query, target interface{}) error {
+	ctx, span, start := c.span(ctx, "unmarshal_one", indexName)
+	err := c.inner.UnmarshalOne(ctx, indexName, query, target)
+	c.finish(span, "unmarshal_one", indexName, start, err)
+	return err
+}
+
+func (c *observableClient) UpdateByQuery(ctx context.Context, index string, query Query, script string) (TaskID, error) {
+	ctx, span, start := c.span(ctx, "update_by_query", index)
+	id, err := c.inner.UpdateByQuery(ctx, index, query, script)
+	c.finish(span, "update_by_query", index, start, err)
+	return id, err
+}
+
+// annotateSearchResult records query body size, hit count, and took_ms on
+// span, as called for by Search's instrumentation.
+func annotateSearchResult(span trace.Span, query Query, res *SearchResult) {
+	attrs := []attribute.KeyValue{
+		attribute.Int("elasticsearch.hit_count", len(res.Hits)),
+		attribute.Int64("elasticsearch.took_ms", res.TookMillis),
+	}
+
+	if query != nil {
+		if src, err := query.Source(); err == nil {
+			if body, err := json.Marshal(src); err == nil {
+				attrs = append(attrs, attribute.Int("elasticsearch.query_body_bytes", len(body)))
+			}
+		}
+	}
+
+	span.SetAttributes(attrs...)
+}
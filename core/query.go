@@ -0,0 +1,46 @@
+package core
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Query abstracts a search query independent of the underlying driver (see
+// ElasticClient's doc comment on the olivere-compatibility of these
+// interfaces).
+type Query interface {
+	Source() (interface{}, error)
+}
+
+// Sorter abstracts a sort clause independent of the underlying driver, for
+// the same reason as Query.
+type Sorter interface {
+	Source() (interface{}, error)
+}
+
+// RawQuery wraps a pre-built JSON query body so it satisfies Query without
+// depending on any driver-specific query builder. Useful for the v8 driver,
+// which speaks raw JSON rather than olivere's query DSL types.
+type RawQuery json.RawMessage
+
+// Source implements Query.
+func (q RawQuery) Source() (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(q, &v); err != nil {
+		return nil, errors.Wrap(err, "Unmarshal")
+	}
+	return v, nil
+}
+
+// RawSorter wraps a pre-built JSON sort clause so it satisfies Sorter.
+type RawSorter json.RawMessage
+
+// Source implements Sorter.
+func (s RawSorter) Source() (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(s, &v); err != nil {
+		return nil, errors.Wrap(err, "Unmarshal")
+	}
+	return v, nil
+}
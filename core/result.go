@@ -0,0 +1,83 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SearchResult is a backend-neutral view of a search response, built from
+// either *elastic.SearchResult (v7) or the raw ES 8 JSON response (v8), so
+// callers don't have to reach into olivere types.
+type SearchResult struct {
+	TookMillis   int64
+	TotalHits    int64
+	MaxScore     *float64
+	Hits         []json.RawMessage
+	ScrollID     string
+	PitID        string
+	Aggregations json.RawMessage
+}
+
+// PingResult is the backend-neutral response to a cluster ping.
+type PingResult struct {
+	Name        string
+	ClusterName string
+	Version     string
+	TagLine     string
+}
+
+// BulkProcessor is a backend-neutral handle to a running bulk processor, as
+// returned by RunBulkProcessor. It mirrors the subset of *elastic.BulkProcessor
+// that callers in this codebase actually use.
+type BulkProcessor interface {
+	Add(request BulkableRequest)
+	Flush() error
+	Close() error
+	Stats() BulkProcessorStats
+}
+
+// BulkableRequest is a single action (index/create/update/delete) that can be
+// submitted to a BulkProcessor.
+type BulkableRequest interface {
+	Source() ([]string, error)
+}
+
+// BulkRetryBackoff controls how RunBulkProcessor retries a bulk commit that
+// failed outright (as opposed to individual item failures within an
+// otherwise successful commit, which neither driver retries). See
+// ElasticClient's doc comment on the olivere-compatibility of these
+// interfaces - v7 callers can keep passing elastic.NewExponentialBackoff
+// etc. straight through.
+type BulkRetryBackoff interface {
+	Next(retry int) (time.Duration, bool)
+}
+
+// BulkBeforeFunc is called immediately before a bulk commit executes, on
+// both drivers.
+type BulkBeforeFunc func(executionID int64, requests []BulkableRequest)
+
+// BulkAfterFunc is called immediately after a bulk commit finishes, on both
+// drivers. result is nil if the commit itself failed (err != nil) before
+// any per-item status was known.
+type BulkAfterFunc func(executionID int64, requests []BulkableRequest, result *BulkCommitResult, err error)
+
+// BulkCommitResult summarizes one bulk commit's outcome, passed to
+// BulkAfterFunc. It mirrors the handful of fields from elastic.BulkResponse
+// (v7) and the raw bulk response (v8) that callers actually need.
+type BulkCommitResult struct {
+	Succeeded int64
+	Failed    int64
+}
+
+// BulkProcessorStats mirrors elastic.BulkProcessorStats' shape without
+// depending on the olivere package.
+type BulkProcessorStats struct {
+	Flushed   int64
+	Committed int64
+	Indexed   int64
+	Created   int64
+	Updated   int64
+	Deleted   int64
+	Succeeded int64
+	Failed    int64
+}
@@ -0,0 +1,45 @@
+package core
+
+// TaskID identifies a long-running operation (reindex, update-by-query,
+// delete-by-query) started via the Elasticsearch tasks API.
+type TaskID string
+
+// ReindexParams holds all required and optional parameters for Reindex.
+type ReindexParams struct {
+	SourceIndex string
+	SourceQuery Query
+	DestIndex   string
+
+	// Slices parallelizes the reindex across shards. Pass "auto" to let ES
+	// pick, an int for a fixed slice count, or leave zero for no slicing.
+	Slices interface{}
+
+	// RequestsPerSecond throttles the operation; 0 means unthrottled.
+	RequestsPerSecond int
+}
+
+// TaskStatus is the backend-neutral status of a task started by Reindex,
+// UpdateByQuery, or DeleteByQuery.
+type TaskStatus struct {
+	Completed        bool
+	Total            int64
+	Created          int64
+	Updated          int64
+	Deleted          int64
+	Batches          int64
+	VersionConflicts int64
+	Noops            int64
+	Error            string
+}
+
+// taskStatusWire mirrors the JSON shape of the "status" object ES reports
+// for reindex/update-by-query/delete-by-query tasks, shared by both drivers.
+type taskStatusWire struct {
+	Total            int64 `json:"total"`
+	Created          int64 `json:"created"`
+	Updated          int64 `json:"updated"`
+	Deleted          int64 `json:"deleted"`
+	Batches          int64 `json:"batches"`
+	VersionConflicts int64 `json:"version_conflicts"`
+	Noops            int64 `json:"noops"`
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
+)
+
+func (c *ElasticClientImpl) Reindex(ctx context.Context, p *ReindexParams) (TaskID, error) {
+	svc := c.client.Reindex().
+		SourceIndex(p.SourceIndex).
+		DestinationIndex(p.DestIndex)
+
+	if p.SourceQuery != nil {
+		src := elastic.NewReindexSource().Index(p.SourceIndex).Query(p.SourceQuery)
+		svc = svc.Source(src)
+	}
+
+	if p.Slices != nil {
+		svc = svc.Slices(p.Slices)
+	}
+
+	if p.RequestsPerSecond != 0 {
+		svc = svc.RequestsPerSecond(p.RequestsPerSecond)
+	}
+
+	res, err := svc.DoAsync(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "DoAsync")
+	}
+
+	return TaskID(res.TaskId), nil
+}
+
+func (c *ElasticClientImpl) UpdateByQuery(ctx context.Context, index string, query Query, script string) (TaskID, error) {
+	svc := c.client.UpdateByQuery(index).Query(query)
+	if script != "" {
+		svc = svc.Script(elastic.NewScript(script))
+	}
+
+	res, err := svc.DoAsync(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "DoAsync")
+	}
+
+	return TaskID(res.TaskId), nil
+}
+
+func (c *ElasticClientImpl) DeleteByQuery(ctx context.Context, index string, query Query) (TaskID, error) {
+	res, err := c.client.DeleteByQuery(index).Query(query).DoAsync(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "DoAsync")
+	}
+
+	return TaskID(res.TaskId), nil
+}
+
+func (c *ElasticClientImpl) GetTask(ctx context.Context, id TaskID) (*TaskStatus, error) {
+	res, err := elastic.NewTasksGetTaskService(c.client).TaskId(string(id)).Do(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Do")
+	}
+
+	status := &TaskStatus{Completed: res.Completed}
+
+	if res.Error != nil {
+		status.Error = res.Error.Reason
+	}
+
+	if res.Task != nil && res.Task.Status != nil {
+		raw, err := json.Marshal(res.Task.Status)
+		if err != nil {
+			return nil, errors.Wrap(err, "Marshal")
+		}
+
+		var wire taskStatusWire
+		if err := json.Unmarshal(raw, &wire); err != nil {
+			return nil, errors.Wrap(err, "Unmarshal")
+		}
+
+		status.Total = wire.Total
+		status.Created = wire.Created
+		status.Updated = wire.Updated
+		status.Deleted = wire.Deleted
+		status.Batches = wire.Batches
+		status.VersionConflicts = wire.VersionConflicts
+		status.Noops = wire.Noops
+	}
+
+	return status, nil
+}
+
+func (c *ElasticClientImpl) CancelTask(ctx context.Context, id TaskID) error {
+	_, err := elastic.NewTasksCancelService(c.client).TaskId(string(id)).Do(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Do")
+	}
+
+	return nil
+}
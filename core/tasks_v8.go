@@ -0,0 +1,180 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/pkg/errors"
+)
+
+func (c *ElasticClientV8) Reindex(ctx context.Context, p *ReindexParams) (TaskID, error) {
+	source := map[string]interface{}{"index": p.SourceIndex}
+	if p.SourceQuery != nil {
+		q, err := p.SourceQuery.Source()
+		if err != nil {
+			return "", errors.Wrap(err, "Source")
+		}
+		source["query"] = q
+	}
+
+	body, err := encodeBody(map[string]interface{}{
+		"source": source,
+		"dest":   map[string]interface{}{"index": p.DestIndex},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	opts := []func(*esapi.ReindexRequest){
+		c.client.Reindex.WithContext(ctx),
+		c.client.Reindex.WithWaitForCompletion(false),
+	}
+	if p.RequestsPerSecond != 0 {
+		opts = append(opts, c.client.Reindex.WithRequestsPerSecond(p.RequestsPerSecond))
+	}
+	if p.Slices != nil {
+		opts = append(opts, c.client.Reindex.WithSlices(p.Slices))
+	}
+
+	res, err := c.client.Reindex(body, opts...)
+	if err != nil {
+		return "", errors.Wrap(err, "Reindex")
+	}
+
+	return decodeStartTaskResult(res)
+}
+
+func (c *ElasticClientV8) UpdateByQuery(ctx context.Context, index string, query Query, script string) (TaskID, error) {
+	body := map[string]interface{}{}
+
+	q, err := querySource(query)
+	if err != nil {
+		return "", errors.Wrap(err, "Source")
+	}
+	body["query"] = q
+
+	if script != "" {
+		body["script"] = map[string]interface{}{"source": script}
+	}
+
+	buf, err := encodeBody(body)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.client.UpdateByQuery(
+		[]string{index},
+		c.client.UpdateByQuery.WithContext(ctx),
+		c.client.UpdateByQuery.WithBody(buf),
+		c.client.UpdateByQuery.WithWaitForCompletion(false),
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "UpdateByQuery")
+	}
+
+	return decodeStartTaskResult(res)
+}
+
+func (c *ElasticClientV8) DeleteByQuery(ctx context.Context, index string, query Query) (TaskID, error) {
+	q, err := querySource(query)
+	if err != nil {
+		return "", errors.Wrap(err, "Source")
+	}
+
+	buf, err := encodeBody(map[string]interface{}{"query": q})
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.client.DeleteByQuery(
+		[]string{index},
+		buf,
+		c.client.DeleteByQuery.WithContext(ctx),
+		c.client.DeleteByQuery.WithWaitForCompletion(false),
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "DeleteByQuery")
+	}
+
+	return decodeStartTaskResult(res)
+}
+
+// decodeStartTaskResult decodes the {"task": "<node>:<id>"} body the async
+// reindex/update-by-query/delete-by-query endpoints return once
+// wait_for_completion=false, shared across all three callers above.
+func decodeStartTaskResult(res *esapi.Response) (TaskID, error) {
+	if err := decodeV8Error(res); err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var out struct {
+		Task string `json:"task"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return "", errors.Wrap(err, "Decode")
+	}
+
+	return TaskID(out.Task), nil
+}
+
+func (c *ElasticClientV8) GetTask(ctx context.Context, id TaskID) (*TaskStatus, error) {
+	res, err := c.client.Tasks.Get(string(id), c.client.Tasks.Get.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "Get")
+	}
+
+	if err := decodeV8Error(res); err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var out struct {
+		Completed bool `json:"completed"`
+		Task      struct {
+			Status json.RawMessage `json:"status"`
+		} `json:"task"`
+		Error *struct {
+			Reason string `json:"reason"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, errors.Wrap(err, "Decode")
+	}
+
+	status := &TaskStatus{Completed: out.Completed}
+	if out.Error != nil {
+		status.Error = out.Error.Reason
+	}
+
+	if len(out.Task.Status) != 0 {
+		var wire taskStatusWire
+		if err := json.Unmarshal(out.Task.Status, &wire); err != nil {
+			return nil, errors.Wrap(err, "Unmarshal")
+		}
+
+		status.Total = wire.Total
+		status.Created = wire.Created
+		status.Updated = wire.Updated
+		status.Deleted = wire.Deleted
+		status.Batches = wire.Batches
+		status.VersionConflicts = wire.VersionConflicts
+		status.Noops = wire.Noops
+	}
+
+	return status, nil
+}
+
+func (c *ElasticClientV8) CancelTask(ctx context.Context, id TaskID) error {
+	res, err := c.client.Tasks.Cancel(
+		c.client.Tasks.Cancel.WithContext(ctx),
+		c.client.Tasks.Cancel.WithTaskID(string(id)),
+	)
+	if err != nil {
+		return errors.Wrap(err, "Cancel")
+	}
+	defer res.Body.Close()
+
+	return decodeV8Error(res)
+}
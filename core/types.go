@@ -8,8 +8,9 @@ import (
 
 type (
 
-	// elasticWrapper implements Client
-	ElasticClient struct {
+	// ElasticClientImpl is the olivere/elastic/v7 driver: it talks to ES 6/7
+	// clusters and implements ElasticClient.
+	ElasticClientImpl struct {
 		client   *elastic.Client
 		endpoint string
 		userName string
@@ -19,11 +20,21 @@ type (
 	// SearchParameters holds all required and optional parameters for executing a search
 	SearchParameters struct {
 		Index       string
-		Query       elastic.Query
+		Query       Query
 		From        int
 		PageSize    int
-		Sorter      []elastic.Sorter
+		Sorter      []Sorter
 		SearchAfter []interface{}
+
+		// Aggregations, keyed by aggregation name, are attached to the
+		// request alongside Query. Decode results with DecodeTerms,
+		// DecodeDateHistogram, DecodeStats, DecodeCardinality, DecodeNested,
+		// DecodeFilters, or DecodeComposite.
+		Aggregations map[string]Aggregation
+
+		// AggregateOnly sets size: 0, so the response carries only
+		// Aggregations and no hits.
+		AggregateOnly bool
 	}
 
 	// BulkProcessorParameters holds all required and optional parameters for executing bulk service
@@ -33,8 +44,12 @@ type (
 		BulkActions   int
 		BulkSize      int
 		FlushInterval time.Duration
-		Backoff       elastic.Backoff
-		BeforeFunc    elastic.BulkBeforeFunc
-		AfterFunc     elastic.BulkAfterFunc
+
+		// Backoff, BeforeFunc, and AfterFunc are backend-neutral - both the
+		// v7 and v8 drivers honor them, the v7 driver by adapting them to
+		// olivere's BulkProcessorService.Backoff/Before/After.
+		Backoff    BulkRetryBackoff
+		BeforeFunc BulkBeforeFunc
+		AfterFunc  BulkAfterFunc
 	}
 )
@@ -0,0 +1,922 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+// ElasticClientV8 is the go-elasticsearch/v8 driver: it talks to ES 8
+// clusters and implements ElasticClient. Unlike ElasticClientImpl it speaks
+// raw JSON rather than a typed query DSL, since the v8 client's typed API
+// surface isn't a drop-in replacement for olivere's query builders.
+type ElasticClientV8 struct {
+	client   *elasticsearch.Client
+	endpoint string
+}
+
+// NewClientV8 dials an ES 8 cluster using the go-elasticsearch/v8 driver.
+//
+// Deprecated: use NewClientV8FromConfig, which also supports TLS/transport
+// overrides.
+func NewClientV8(endpoint, userName, password string) (*ElasticClientV8, error) {
+	return NewClientV8FromConfig(Config{
+		Endpoint: endpoint,
+		Username: userName,
+		Password: password,
+	})
+}
+
+// NewClientV8FromConfig dials an ES 8 cluster using the go-elasticsearch/v8 driver.
+func NewClientV8FromConfig(cfg Config) (*ElasticClientV8, error) {
+	esCfg := elasticsearch.Config{
+		Addresses: []string{cfg.Endpoint},
+		Username:  cfg.Username,
+		Password:  cfg.Password,
+	}
+
+	if httpClient := cfg.httpClient(); httpClient != nil {
+		esCfg.Transport = httpClient.Transport
+	}
+
+	client, err := elasticsearch.NewClient(esCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewClient")
+	}
+
+	return &ElasticClientV8{client: client, endpoint: cfg.Endpoint}, nil
+}
+
+// decodeV8Error checks res for an error response, closing and draining the
+// body itself when there is one. On the success path it leaves the body
+// open and unread, since the caller still needs to decode it - callers own
+// closing it via their own defer in that case.
+func decodeV8Error(res *esapi.Response) error {
+	if !res.IsError() {
+		return nil
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	return errors.Errorf("elasticsearch: %s: %s", res.Status(), string(body))
+}
+
+func querySource(q Query) (interface{}, error) {
+	if q == nil {
+		return map[string]interface{}{"match_all": map[string]interface{}{}}, nil
+	}
+	return q.Source()
+}
+
+func encodeBody(v interface{}) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return nil, errors.Wrap(err, "Encode")
+	}
+	return buf, nil
+}
+
+type v8SearchResponse struct {
+	ScrollID string `json:"_scroll_id"`
+	PitID    string `json:"pit_id"`
+	Took     int64  `json:"took"`
+	Hits     struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		MaxScore *float64 `json:"max_score"`
+		Hits     []struct {
+			Source json.RawMessage `json:"_source"`
+			Sort   []interface{}   `json:"sort"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations json.RawMessage `json:"aggregations"`
+}
+
+func (r *v8SearchResponse) toSearchResult() *SearchResult {
+	hits := make([]json.RawMessage, len(r.Hits.Hits))
+	for i, hit := range r.Hits.Hits {
+		hits[i] = hit.Source
+	}
+
+	return &SearchResult{
+		TookMillis:   r.Took,
+		TotalHits:    r.Hits.Total.Value,
+		MaxScore:     r.Hits.MaxScore,
+		Hits:         hits,
+		ScrollID:     r.ScrollID,
+		PitID:        r.PitID,
+		Aggregations: r.Aggregations,
+	}
+}
+
+func sortersToSource(sorters []Sorter) ([]interface{}, error) {
+	sort := make([]interface{}, len(sorters))
+	for i, s := range sorters {
+		src, err := s.Source()
+		if err != nil {
+			return nil, errors.Wrap(err, "Source")
+		}
+		sort[i] = src
+	}
+	return sort, nil
+}
+
+// buildSearchOpts builds the esapi.SearchRequest options common to Search
+// and searchWithScroll from a SearchParameters.
+func (c *ElasticClientV8) buildSearchOpts(ctx context.Context, p *SearchParameters) ([]func(*esapi.SearchRequest), error) {
+	query, err := querySource(p.Query)
+	if err != nil {
+		return nil, errors.Wrap(err, "Source")
+	}
+
+	body := map[string]interface{}{"query": query}
+
+	if len(p.Sorter) != 0 {
+		sort, err := sortersToSource(p.Sorter)
+		if err != nil {
+			return nil, err
+		}
+		body["sort"] = sort
+	}
+
+	if len(p.SearchAfter) != 0 {
+		body["search_after"] = p.SearchAfter
+	}
+
+	if len(p.Aggregations) != 0 {
+		aggs := make(map[string]interface{}, len(p.Aggregations))
+		for name, agg := range p.Aggregations {
+			src, err := agg.Source()
+			if err != nil {
+				return nil, errors.Wrap(err, "Source")
+			}
+			aggs[name] = src
+		}
+		body["aggs"] = aggs
+	}
+
+	buf, err := encodeBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []func(*esapi.SearchRequest){
+		c.client.Search.WithContext(ctx),
+		c.client.Search.WithIndex(p.Index),
+		c.client.Search.WithBody(buf),
+		c.client.Search.WithFrom(p.From),
+	}
+
+	if p.PageSize != 0 {
+		opts = append(opts, c.client.Search.WithSize(p.PageSize))
+	}
+
+	if p.AggregateOnly {
+		opts = append(opts, c.client.Search.WithSize(0))
+	}
+
+	return opts, nil
+}
+
+func (c *ElasticClientV8) Search(ctx context.Context, p *SearchParameters) (*SearchResult, error) {
+	opts, err := c.buildSearchOpts(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.client.Search(opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Search")
+	}
+
+	if err := decodeV8Error(res); err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var out v8SearchResponse
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, errors.Wrap(err, "Decode")
+	}
+
+	return out.toSearchResult(), nil
+}
+
+// searchWithScroll is Search plus a scroll TTL, so the response carries a
+// _scroll_id EnumerateItems can feed into client.Scroll to page past the
+// first batch - without WithScroll, ES returns no scroll_id and the
+// follow-up Scroll calls have nothing to page from.
+func (c *ElasticClientV8) searchWithScroll(ctx context.Context, p *SearchParameters, scrollTTL time.Duration) (*SearchResult, error) {
+	opts, err := c.buildSearchOpts(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, c.client.Search.WithScroll(scrollTTL))
+
+	res, err := c.client.Search(opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Search")
+	}
+
+	if err := decodeV8Error(res); err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var out v8SearchResponse
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, errors.Wrap(err, "Decode")
+	}
+
+	return out.toSearchResult(), nil
+}
+
+func (c *ElasticClientV8) SearchWithDSL(ctx context.Context, index, query string) (*SearchResult, error) {
+	res, err := c.client.Search(
+		c.client.Search.WithContext(ctx),
+		c.client.Search.WithIndex(index),
+		c.client.Search.WithBody(strings.NewReader(query)),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Search")
+	}
+
+	if err := decodeV8Error(res); err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var out v8SearchResponse
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, errors.Wrap(err, "Decode")
+	}
+
+	return out.toSearchResult(), nil
+}
+
+func (c *ElasticClientV8) UnmarshalOne(ctx context.Context, indexName string, query Query, target interface{}) error {
+	res, err := c.Search(ctx, &SearchParameters{Index: indexName, Query: query, PageSize: 1})
+	if err != nil {
+		return errors.Wrap(err, "Search")
+	}
+
+	if res.TotalHits == 0 {
+		return ErrEmptyResult
+	}
+
+	if err := json.Unmarshal(res.Hits[0], target); err != nil {
+		return errors.Wrap(err, "Unmarshal")
+	}
+
+	return nil
+}
+
+func (c *ElasticClientV8) UnmarshalMostRecent(ctx context.Context, indexName string, query Query, timestampField string, target interface{}) error {
+	res, err := c.Search(ctx, &SearchParameters{
+		Index:    indexName,
+		Query:    query,
+		PageSize: 1,
+		Sorter:   []Sorter{RawSorter(`{"` + timestampField + `":"desc"}`)},
+	})
+	if err != nil {
+		return errors.Wrap(err, "Search")
+	}
+
+	if res.TotalHits == 0 {
+		return ErrEmptyResult
+	}
+
+	if err := json.Unmarshal(res.Hits[0], target); err != nil {
+		return errors.Wrap(err, "Unmarshal")
+	}
+
+	return nil
+}
+
+func (c *ElasticClientV8) Count(ctx context.Context, index, query string) (int64, error) {
+	res, err := c.client.Count(
+		c.client.Count.WithContext(ctx),
+		c.client.Count.WithIndex(index),
+		c.client.Count.WithBody(strings.NewReader(query)),
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Count")
+	}
+
+	if err := decodeV8Error(res); err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	var out struct {
+		Count int64 `json:"count"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return 0, errors.Wrap(err, "Decode")
+	}
+
+	return out.Count, nil
+}
+
+func (c *ElasticClientV8) FlushIndex(ctx context.Context, index string) error {
+	res, err := c.client.Indices.Flush(
+		c.client.Indices.Flush.WithContext(ctx),
+		c.client.Indices.Flush.WithIndex(index),
+	)
+	if err != nil {
+		return errors.Wrap(err, "Flush")
+	}
+	defer res.Body.Close()
+
+	return decodeV8Error(res)
+}
+
+func (c *ElasticClientV8) EnsureIndexWithMapping(ctx context.Context, indexName, mapping string) error {
+	existsRes, err := c.client.Indices.Exists([]string{indexName}, c.client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "Exists")
+	}
+	defer existsRes.Body.Close()
+
+	if existsRes.StatusCode == 200 {
+		return nil
+	}
+
+	res, err := c.client.Indices.Create(
+		indexName,
+		c.client.Indices.Create.WithContext(ctx),
+		c.client.Indices.Create.WithBody(strings.NewReader(mapping)),
+	)
+	if err != nil {
+		return errors.Wrap(err, "Create")
+	}
+
+	if err := decodeV8Error(res); err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var out struct {
+		Acknowledged bool `json:"acknowledged"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+
+	if !out.Acknowledged {
+		return ErrNotAcknowledged
+	}
+
+	return nil
+}
+
+func (c *ElasticClientV8) CreateIndex(ctx context.Context, index string) error {
+	res, err := c.client.Indices.Create(index, c.client.Indices.Create.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "Create")
+	}
+	defer res.Body.Close()
+
+	return decodeV8Error(res)
+}
+
+func (c *ElasticClientV8) PutMapping(ctx context.Context, index, root, key, valueType string) error {
+	body, err := encodeBody(buildPutMappingBody(root, key, valueType))
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.Indices.PutMapping(
+		[]string{index},
+		body,
+		c.client.Indices.PutMapping.WithContext(ctx),
+	)
+	if err != nil {
+		return errors.Wrap(err, "PutMapping")
+	}
+	defer res.Body.Close()
+
+	return decodeV8Error(res)
+}
+
+func (c *ElasticClientV8) doIndex(ctx context.Context, indexName string, data map[string]interface{}, opType string) error {
+	if len(data) > bulkIngestThreshold {
+		action := BulkIndex
+		if opType == "create" {
+			action = BulkCreate
+		}
+		return ingestViaBulk(ctx, c, indexName, data, action)
+	}
+
+	for id, doc := range data {
+		buf, err := encodeBody(doc)
+		if err != nil {
+			return err
+		}
+
+		opts := []func(*esapi.IndexRequest){
+			c.client.Index.WithContext(ctx),
+			c.client.Index.WithDocumentID(id),
+		}
+		if opType != "" {
+			opts = append(opts, c.client.Index.WithOpType(opType))
+		}
+
+		res, err := c.client.Index(indexName, buf, opts...)
+		if err != nil {
+			return errors.Wrap(err, "Index")
+		}
+
+		if err := decodeV8Error(res); err != nil {
+			return err
+		}
+		res.Body.Close()
+	}
+
+	return nil
+}
+
+func (c *ElasticClientV8) DoIndex(ctx context.Context, indexName string, data map[string]interface{}) error {
+	return c.doIndex(ctx, indexName, data, "")
+}
+
+func (c *ElasticClientV8) DoCreate(ctx context.Context, indexName string, data map[string]interface{}) error {
+	return c.doIndex(ctx, indexName, data, "create")
+}
+
+func (c *ElasticClientV8) DoIndexWithNameProvider(ctx context.Context, data map[string]IndexNameProvider) error {
+	if len(data) > bulkIngestThreshold {
+		return ingestViaBulkWithNameProvider(ctx, c, data)
+	}
+
+	for id, doc := range data {
+		buf, err := encodeBody(doc)
+		if err != nil {
+			return err
+		}
+
+		res, err := c.client.Index(
+			doc.IndexName(),
+			buf,
+			c.client.Index.WithContext(ctx),
+			c.client.Index.WithDocumentID(id),
+		)
+		if err != nil {
+			return errors.Wrap(err, "Index")
+		}
+
+		if err := decodeV8Error(res); err != nil {
+			return err
+		}
+		res.Body.Close()
+	}
+
+	return nil
+}
+
+func (c *ElasticClientV8) MarshalWithNameAndIDProvider(ctx context.Context, data IndexNameAndIDProvider) error {
+	buf, err := encodeBody(data)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.Index(
+		data.IndexName(),
+		buf,
+		c.client.Index.WithContext(ctx),
+		c.client.Index.WithDocumentID(data.ID()),
+	)
+	if err != nil {
+		return errors.Wrap(err, "Index")
+	}
+	defer res.Body.Close()
+
+	return decodeV8Error(res)
+}
+
+// GetIndices returns, for every prefix, the names of indices matched by
+// GetIndicesInfo with that prefix (a "<prefix>*" wildcard; see
+// GetIndicesOptions.Prefixes).
+//
+// Deprecated: use GetIndicesInfo, which also covers date-suffixed and
+// ILM-rollover index names, aliases, and per-index stats.
+func (c *ElasticClientV8) GetIndices(prefixes []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+
+	infos, err := c.GetIndicesInfo(context.Background(), GetIndicesOptions{Prefixes: prefixes})
+	if err != nil {
+		return result, err
+	}
+
+	for _, prefix := range prefixes {
+		for _, info := range infos {
+			if strings.HasPrefix(info.Name, prefix) {
+				result[prefix] = append(result[prefix], info.Name)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// catIndexRow is the Cat Indices API response fields GetIndicesInfo needs.
+// The v8 driver decodes it directly since it speaks raw JSON rather than
+// olivere's typed CatIndicesResponseRow.
+type catIndexRow struct {
+	Index        string `json:"index"`
+	Health       string `json:"health"`
+	DocsCount    string `json:"docs.count"`
+	StoreSize    string `json:"store.size"`
+	CreationDate string `json:"creation.date"`
+}
+
+// catAliasRowV8 is the Cat Aliases API response fields GetIndicesInfo needs.
+type catAliasRowV8 struct {
+	Index        string `json:"index"`
+	Alias        string `json:"alias"`
+	IsWriteIndex string `json:"is_write_index"`
+}
+
+// GetIndicesInfo lists indices (and, with IncludeAliases, their aliases) via
+// the Cat Indices and Cat Aliases APIs.
+func (c *ElasticClientV8) GetIndicesInfo(ctx context.Context, opts GetIndicesOptions) ([]IndexInfo, error) {
+	catOpts := []func(*esapi.CatIndicesRequest){
+		c.client.Cat.Indices.WithContext(ctx),
+		c.client.Cat.Indices.WithFormat("json"),
+		c.client.Cat.Indices.WithBytes("b"),
+	}
+
+	if patterns := buildIndexPatterns(opts.Prefixes, opts.IncludeHidden); len(patterns) != 0 {
+		catOpts = append(catOpts, c.client.Cat.Indices.WithIndex(patterns...))
+	}
+
+	if len(opts.SortBy) != 0 {
+		catOpts = append(catOpts, c.client.Cat.Indices.WithS(opts.SortBy...))
+	}
+
+	res, err := c.client.Cat.Indices(catOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Indices")
+	}
+
+	if err := decodeV8Error(res); err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var rows []catIndexRow
+	if err := json.NewDecoder(res.Body).Decode(&rows); err != nil {
+		return nil, errors.Wrap(err, "Decode")
+	}
+
+	infos := make([]IndexInfo, len(rows))
+	for i, row := range rows {
+		infos[i] = IndexInfo{
+			Name:           row.Index,
+			DocCount:       parseByteCount(row.DocsCount),
+			StoreSizeBytes: parseByteCount(row.StoreSize),
+			Health:         row.Health,
+			CreationDate:   parseByteCount(row.CreationDate),
+		}
+	}
+
+	if !opts.IncludeAliases {
+		return infos, nil
+	}
+
+	aliasRes, err := c.client.Cat.Aliases(
+		c.client.Cat.Aliases.WithContext(ctx),
+		c.client.Cat.Aliases.WithFormat("json"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Aliases")
+	}
+
+	if err := decodeV8Error(aliasRes); err != nil {
+		return nil, err
+	}
+	defer aliasRes.Body.Close()
+
+	var aliasRows []catAliasRowV8
+	if err := json.NewDecoder(aliasRes.Body).Decode(&aliasRows); err != nil {
+		return nil, errors.Wrap(err, "Decode [aliases]")
+	}
+
+	aliases := make([]catAliasRow, len(aliasRows))
+	for i, row := range aliasRows {
+		aliases[i] = catAliasRow{
+			Index:        row.Index,
+			Alias:        row.Alias,
+			IsWriteIndex: row.IsWriteIndex == "true",
+		}
+	}
+
+	return expandWithAliases(infos, aliases), nil
+}
+
+// Bulk sends items to the _bulk endpoint in a single request, reusing the
+// driver-neutral NDJSON encoding shared with the v7 driver.
+func (c *ElasticClientV8) Bulk(ctx context.Context, items []BulkItem) (*BulkResult, error) {
+	body, err := buildBulkBody(items)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.client.Bulk(bytes.NewReader(body), c.client.Bulk.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "Bulk")
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		respBody, _ := io.ReadAll(res.Body)
+		return nil, errors.Errorf("elasticsearch: %s: %s", res.Status(), string(respBody))
+	}
+
+	respBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "ReadAll")
+	}
+
+	return parseBulkResponse(respBody)
+}
+
+func (c *ElasticClientV8) ClearScroll(ctx context.Context, scrollID string) error {
+	if scrollID == "" {
+		return nil
+	}
+
+	res, err := c.client.ClearScroll(
+		c.client.ClearScroll.WithContext(ctx),
+		c.client.ClearScroll.WithScrollID(scrollID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "ClearScroll")
+	}
+	defer res.Body.Close()
+
+	return decodeV8Error(res)
+}
+
+// OpenPIT opens a point in time against index, valid until keepAlive
+// elapses or ClosePIT is called. It underlies EnumerateItemsPIT and
+// EnumerateItemsParallel.
+func (c *ElasticClientV8) OpenPIT(ctx context.Context, index string, keepAlive time.Duration) (string, error) {
+	res, err := c.client.OpenPointInTime(
+		[]string{index},
+		keepAlive.String(),
+		c.client.OpenPointInTime.WithContext(ctx),
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "OpenPointInTime")
+	}
+
+	if err := decodeV8Error(res); err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var out struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return "", errors.Wrap(err, "Decode")
+	}
+
+	return out.ID, nil
+}
+
+// ClosePIT releases a point in time opened with OpenPIT.
+func (c *ElasticClientV8) ClosePIT(ctx context.Context, id string) error {
+	buf, err := encodeBody(map[string]interface{}{"id": id})
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ClosePointInTime(
+		c.client.ClosePointInTime.WithContext(ctx),
+		c.client.ClosePointInTime.WithBody(buf),
+	)
+	if err != nil {
+		return errors.Wrap(err, "ClosePointInTime")
+	}
+	defer res.Body.Close()
+
+	return decodeV8Error(res)
+}
+
+// SearchAfterPage runs a single page of a point-in-time + search_after
+// search. It underlies EnumerateItemsPIT and EnumerateItemsParallel, which
+// drive it in a loop, feeding SearchAfterResult.LastSort back in as the
+// next page's SearchAfter.
+func (c *ElasticClientV8) SearchAfterPage(ctx context.Context, p *SearchAfterParameters) (*SearchAfterResult, error) {
+	query, err := querySource(p.Query)
+	if err != nil {
+		return nil, errors.Wrap(err, "Source")
+	}
+
+	sort, err := sortersToSource(p.Sorter)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"query": query,
+		"sort":  sort,
+		"pit": map[string]interface{}{
+			"id": p.PitID,
+		},
+	}
+
+	if p.PageSize != 0 {
+		body["size"] = p.PageSize
+	}
+
+	if len(p.SearchAfter) != 0 {
+		body["search_after"] = p.SearchAfter
+	}
+
+	if p.Slice != nil {
+		body["slice"] = map[string]interface{}{
+			"id":  p.Slice.ID,
+			"max": p.Slice.Max,
+		}
+	}
+
+	buf, err := encodeBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.client.Search(c.client.Search.WithContext(ctx), c.client.Search.WithBody(buf))
+	if err != nil {
+		return nil, errors.Wrap(err, "Search")
+	}
+
+	if err := decodeV8Error(res); err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var out v8SearchAfterResponse
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, errors.Wrap(err, "Decode")
+	}
+
+	return out.toSearchAfterResult(), nil
+}
+
+type v8SearchAfterResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Source json.RawMessage `json:"_source"`
+			Sort   []interface{}   `json:"sort"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func (r *v8SearchAfterResponse) toSearchAfterResult() *SearchAfterResult {
+	hits := make([]json.RawMessage, len(r.Hits.Hits))
+
+	var lastSort []interface{}
+	for i, hit := range r.Hits.Hits {
+		hits[i] = hit.Source
+		lastSort = hit.Sort
+	}
+
+	return &SearchAfterResult{
+		Hits:      hits,
+		TotalHits: r.Hits.Total.Value,
+		LastSort:  lastSort,
+	}
+}
+
+func (c *ElasticClientV8) EnumerateItems(
+	ctx context.Context,
+	indexName string,
+	query Query,
+	sorter Sorter,
+	onItem func(item json.RawMessage, nCurrentItem, nTotalItems int64, commit bool) error,
+) error {
+
+	errs := new(multierror.Error)
+
+	var sorters []Sorter
+	if sorter != nil {
+		sorters = []Sorter{sorter}
+	}
+
+	res, err := c.searchWithScroll(ctx, &SearchParameters{Index: indexName, Query: query, PageSize: 1000, Sorter: sorters}, time.Minute)
+	if err != nil {
+		return errors.Wrap(err, "Search")
+	}
+
+	var nCurrentItem int64
+	scrollID := res.ScrollID
+
+	for {
+		nBatchItems := len(res.Hits)
+		for idx, hit := range res.Hits {
+			nCurrentItem++
+			commit := idx == nBatchItems-1
+			if err := onItem(hit, nCurrentItem, res.TotalHits, commit); err != nil {
+				errs = multierror.Append(errs, errors.Wrap(err, "onItem"))
+				break
+			}
+		}
+
+		if len(errs.Errors) != 0 || nBatchItems == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		sres, err := c.client.Scroll(
+			c.client.Scroll.WithContext(ctx),
+			c.client.Scroll.WithScrollID(scrollID),
+			c.client.Scroll.WithScroll(time.Minute),
+		)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrap(err, "Scroll"))
+			break
+		}
+
+		if err := decodeV8Error(sres); err != nil {
+			errs = multierror.Append(errs, err)
+			break
+		}
+
+		var out v8SearchResponse
+		if err := json.NewDecoder(sres.Body).Decode(&out); err != nil {
+			sres.Body.Close()
+			errs = multierror.Append(errs, errors.Wrap(err, "Decode"))
+			break
+		}
+		sres.Body.Close()
+
+		res = out.toSearchResult()
+		scrollID = res.ScrollID
+	}
+
+	if err := c.ClearScroll(ctx, scrollID); err != nil {
+		errs = multierror.Append(errs, errors.Wrap(err, "ClearScroll"))
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func (c *ElasticClientV8) Ping(ctx context.Context) (*PingResult, error) {
+	res, err := c.client.Info(c.client.Info.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "Info")
+	}
+
+	if err := decodeV8Error(res); err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var out struct {
+		Name        string `json:"name"`
+		ClusterName string `json:"cluster_name"`
+		Version     struct {
+			Number string `json:"number"`
+		} `json:"version"`
+		TagLine string `json:"tagline"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, errors.Wrap(err, "Decode")
+	}
+
+	return &PingResult{
+		Name:        out.Name,
+		ClusterName: out.ClusterName,
+		Version:     out.Version.Number,
+		TagLine:     out.TagLine,
+	}, nil
+}
+
+// RunBulkProcessor buffers BulkableRequest actions and flushes them to the
+// ES 8 _bulk endpoint. The bulk NDJSON wire format is identical across ES
+// versions, so BulkableRequest.Source() (action meta line + optional source
+// line) can be replayed as-is regardless of driver.
+func (c *ElasticClientV8) RunBulkProcessor(ctx context.Context, p *BulkProcessorParameters) (BulkProcessor, error) {
+	return newV8BulkProcessor(ctx, c.client, p), nil
+}
@@ -0,0 +1,217 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+	"github.com/pkg/errors"
+)
+
+// v8BulkProcessor is the ElasticClientV8 counterpart of olivere's
+// *elastic.BulkProcessor: it batches BulkableRequest actions and flushes
+// them to _bulk on a count/interval basis, honoring the same
+// BulkProcessorParameters.Backoff/BeforeFunc/AfterFunc hooks the v7 driver
+// adapts into olivere's BulkProcessorService.
+type v8BulkProcessor struct {
+	client *elasticsearch.Client
+	params *BulkProcessorParameters
+	ctx    context.Context
+
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	requests []BulkableRequest
+	stats    BulkProcessorStats
+	closeCh  chan struct{}
+	doneCh   chan struct{}
+
+	executionID int64
+}
+
+func newV8BulkProcessor(ctx context.Context, client *elasticsearch.Client, p *BulkProcessorParameters) *v8BulkProcessor {
+	bp := &v8BulkProcessor{
+		client:  client,
+		params:  p,
+		ctx:     ctx,
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+
+	if p.FlushInterval > 0 {
+		go bp.flushLoop(ctx, p.FlushInterval)
+	} else {
+		close(bp.doneCh)
+	}
+
+	return bp
+}
+
+func (bp *v8BulkProcessor) flushLoop(ctx context.Context, interval time.Duration) {
+	defer close(bp.doneCh)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-bp.closeCh:
+			return
+		case <-ticker.C:
+			_ = bp.Flush()
+		}
+	}
+}
+
+func (bp *v8BulkProcessor) Add(request BulkableRequest) {
+	bp.mu.Lock()
+	lines, err := request.Source()
+	if err != nil {
+		bp.mu.Unlock()
+		return
+	}
+
+	for _, line := range lines {
+		bp.buf.WriteString(line)
+		bp.buf.WriteByte('\n')
+	}
+	bp.requests = append(bp.requests, request)
+	nInBuf := len(bp.requests)
+	shouldFlush := (bp.params.BulkActions > 0 && nInBuf >= bp.params.BulkActions) ||
+		(bp.params.BulkSize > 0 && bp.buf.Len() >= bp.params.BulkSize)
+	bp.mu.Unlock()
+
+	if shouldFlush {
+		_ = bp.Flush()
+	}
+}
+
+func (bp *v8BulkProcessor) Flush() error {
+	bp.mu.Lock()
+	if len(bp.requests) == 0 {
+		bp.mu.Unlock()
+		return nil
+	}
+
+	body := append([]byte(nil), bp.buf.Bytes()...)
+	requests := bp.requests
+	bp.buf.Reset()
+	bp.requests = nil
+	bp.mu.Unlock()
+
+	executionID := atomic.AddInt64(&bp.executionID, 1)
+
+	if bp.params.BeforeFunc != nil {
+		bp.params.BeforeFunc(executionID, requests)
+	}
+
+	result, err := bp.commitWithRetry(body)
+
+	if bp.params.AfterFunc != nil {
+		bp.params.AfterFunc(executionID, requests, result, err)
+	}
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	if err != nil {
+		bp.stats.Failed += int64(len(requests))
+		return err
+	}
+
+	bp.stats.Flushed++
+	bp.stats.Succeeded += result.Succeeded
+	bp.stats.Failed += result.Failed
+
+	return nil
+}
+
+// commitWithRetry sends body to _bulk, retrying the whole commit per
+// params.Backoff for as long as it keeps returning ok=true - mirroring how
+// olivere's BulkProcessor retries a commit that errored outright, as
+// opposed to individual item failures within a successful commit, which
+// neither driver retries. A nil Backoff means no retries, same as a nil
+// elastic.Backoff would under the v7 driver.
+func (bp *v8BulkProcessor) commitWithRetry(body []byte) (*BulkCommitResult, error) {
+	var lastErr error
+
+	for retry := 0; ; retry++ {
+		result, err := bp.commitOnce(body)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		if bp.params.Backoff == nil {
+			return nil, lastErr
+		}
+
+		wait, ok := bp.params.Backoff.Next(retry)
+		if !ok {
+			return nil, lastErr
+		}
+
+		select {
+		case <-bp.ctx.Done():
+			return nil, bp.ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (bp *v8BulkProcessor) commitOnce(body []byte) (*BulkCommitResult, error) {
+	res, err := bp.client.Bulk(bytes.NewReader(body), bp.client.Bulk.WithContext(bp.ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "Bulk")
+	}
+	defer res.Body.Close()
+
+	if err := decodeV8Error(res); err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, errors.Wrap(err, "Decode")
+	}
+
+	result := &BulkCommitResult{}
+	for _, item := range out.Items {
+		for _, action := range item {
+			if action.Status >= 200 && action.Status < 300 {
+				result.Succeeded++
+			} else {
+				result.Failed++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (bp *v8BulkProcessor) Close() error {
+	err := bp.Flush()
+	select {
+	case <-bp.closeCh:
+	default:
+		close(bp.closeCh)
+	}
+	<-bp.doneCh
+	return err
+}
+
+func (bp *v8BulkProcessor) Stats() BulkProcessorStats {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	return bp.stats
+}